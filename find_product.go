@@ -3,36 +3,77 @@ package main
 import (
 	"io/ioutil"
 	"encoding/json"
+	"flag"
 	"fmt"
 	"log"
 	"os"
 	"strconv"
 	"strings"
+	"time"
 	"warehouse-optimizer/warehouse"
 )
 
 const (
-	gridPath  = "warehouse-grid.csv"
-	dimPath   = "item-dimensions-tabbed.txt"
-	timeLimit = 10.0
+	gridPath     = "warehouse-grid.csv"
+	dimPath      = "item-dimensions-tabbed.txt"
+	capacityPath = "cart-capacity.csv"
+	simPath      = "simulation.csv"
+	costPath     = "cost-model.csv"
+	travelPath   = "travel.csv"
+	layoutPath   = "warehouse-layout.csv"
+	depotsPath   = "depots.csv"
+	classPath    = "item-classes.csv"
+	rulesPath    = "precedence.csv"
+	wavesPath    = "waves.csv"
+	timeLimit    = 10.0
 )
 
 func main() {
+	addr := flag.String("serve", "", "serve the HTTP API on the address, e.g. :8080, instead of asking")
+	render := flag.String("render", "", "draw the routes of a single order or of a batch file on the map, to a .svg or .png file")
+	textMap := flag.Bool("map", false, "draw the route of a single order on a text map")
+	color := flag.Bool("color", false, "colour the text map with ANSI escapes")
+	directions := flag.Bool("directions", false, "tell the route of a single order as walking directions")
+	flag.Parse()
+	if *render != "" && !strings.HasSuffix(*render, ".svg") && !strings.HasSuffix(*render, ".png") {
+		log.Fatal("The map must be rendered to a .svg or a .png file.")
+	}
+	// the layout names the locations of the products and bounds their floors,
+	// so it is set before any product is read
+	warehouse.SetLayout(warehouse.LoadLayout(layoutPath))
 	dim := warehouse.ParesDimensionInfo(dimPath)
 	m := warehouse.ParseProductInfo(gridPath, dim)
-	pathInfo := warehouse.BuildPathInfo(gridPath)
-	fmt.Println("Hello User, where is your worker? e.g.:\"2 4\"")
-	x, y := warehouse.ReadInput()
-	start := warehouse.Point{X: x, Y: y}
-	if x*y%2 == 1 {
+	warehouse.AssignClasses(m, warehouse.ParseClassInfo(classPath))
+	warehouse.SetRules(warehouse.LoadRules(rulesPath))
+	router := warehouse.NewRouter(warehouse.LoadTravelModel(travelPath))
+	pathInfo := warehouse.BuildPathInfo(gridPath, router)
+	capacity := warehouse.LoadCapacity(capacityPath)
+	costModel := warehouse.LoadCostModel(costPath)
+	srv := newServer(m, router, pathInfo, costModel, capacity, warehouse.LoadDepots(depotsPath))
+	grpcOn := startGRPC != nil && startGRPC(srv)
+	if *addr != "" {
+		serve(*addr, srv)
+		return
+	}
+	if grpcOn {
+		select {}
+	}
+	// with several floors a point needs its floor too
+	startHint, endHint := `"2 4" or "A02-B02"`, `"0 18" or "A01-X10"`
+	if warehouse.CurrentLayout().Floors > 1 {
+		startHint, endHint = `"2 4 1" or "F1-A02-B02"`, `"0 18 0" or "F0-A01-X10"`
+	}
+	fmt.Println("Hello User, where is your worker? e.g.:" + startHint)
+	start := warehouse.ReadLocation()
+	if start.X*start.Y%2 == 1 {
 		log.Fatal("Cannot start on a shelf.")
 	}
-	fmt.Println("What is your worker's end location? e.g.:\"0 18\"")
-	x, y = warehouse.ReadInput()
-	end := warehouse.Point{X: x, Y: y}
-	if x*y%2 == 1 {
+	fmt.Println("What is your worker's end location? e.g.:" + endHint)
+	end := warehouse.ReadLocation()
+	if end.X*end.Y%2 == 1 {
 		log.Fatal("Cannot end on a shelf.")
 	}
+	depots := warehouse.LoadDepots(depotsPath).From(start, end)
 	var op, t, iter int
 	fmt.Println("Type 0 for Nearest Neighbor Optimizer, type 1 for Branch & Bound Optimizer (slow!!)")
 	var strInput string
@@ -42,6 +83,9 @@ func main() {
 	}
 	strInput = strings.TrimSpace(strInput)
 	op, err = strconv.Atoi(strInput)
+	if err != nil {
+		log.Fatal(err)
+	}
 	optimizer := func(op int, o warehouse.Order, start, end warehouse.Point, m map[int]warehouse.Product,
 		pathInfo map[warehouse.Point]map[warehouse.Point]float64, iteration ...int) warehouse.Order {
 		if op == 0 {
@@ -50,6 +94,11 @@ func main() {
 			return warehouse.BnBOrderOptimizer(o, start, end, m, pathInfo, timeLimit)
 		}
 	}
+	// opt is the chosen optimizer for the planners
+	opt := func(o warehouse.Order, start, end warehouse.Point, m map[int]warehouse.Product,
+		pathInfo map[warehouse.Point]map[warehouse.Point]float64) warehouse.Order {
+		return optimizer(op, o, start, end, m, pathInfo, iter)
+	}
 	if op == 0 {
 		fmt.Println("What's the max number of iterations you want? (0 for max available)")
 		_, err := fmt.Scan(&strInput)
@@ -58,8 +107,14 @@ func main() {
 		}
 		strInput = strings.TrimSpace(strInput)
 		iter, err = strconv.Atoi(strInput)
+		if err != nil {
+			log.Fatal(err)
+		}
 	}
-	fmt.Println("What's the weight limit of orders? (0 for no limit)")
+	fmt.Println("Type 0 to minimize the travel cost (distance with turn penalties), type 1 to minimize the time.")
+	objective := warehouse.Objective(readInt())
+	costInfo := warehouse.BuildCostInfo(pathInfo, objective, costModel)
+	fmt.Println("What's the weight limit of orders? (0 for no merging of orders)")
 	_, err = fmt.Scan(&strInput)
 	if err != nil {
 		log.Fatal(err)
@@ -67,9 +122,18 @@ func main() {
 	strInput = strings.TrimSpace(strInput)
 	var weight float64
 	weight, err = strconv.ParseFloat(strInput, 64)
+	if err != nil {
+		log.Fatal(err)
+	}
+	// orders are merged into batches up to the weight limit; with 0 every order
+	// keeps its own batch and only the cart's configured capacity splits it
+	merge := weight > 0
+	if merge {
+		capacity.Weight = weight
+	}
 
 	for {
-		fmt.Println("Type 1 to manual input, type 2 to file input.")
+		fmt.Println("Type 1 to manual input, type 2 to file input, type 3 to plan a fleet of workers, type 4 to plan zone picking, type 5 to add items to a route in progress, type 6 to plan waves from an order stream, type 7 to replay a saved plan.")
 		_, err := fmt.Scan(&strInput)
 		if err != nil {
 			log.Fatal(err)
@@ -79,7 +143,7 @@ func main() {
 		if err != nil {
 			log.Fatal(err)
 		}
-		if t == 1 || t == 2 {
+		if t >= 1 && t <= 7 {
 			break
 		}
 	}
@@ -88,68 +152,246 @@ func main() {
 		orders := warehouse.ReadOrder(m)
 		fmt.Println("Here is the optimal picking order:")
 		//optimalOrder := warehouse.BruteForceOrderOptimizer(orders[0], start, end, m, pathInfo)
-		optimalOrder := optimizer(op, orders[0], start, end, m, pathInfo, iter)
+		optimalOrder, start, end, err := warehouse.DepotOrderOptimizer(orders[0], depots, m, costInfo, opt)
+		if err != nil {
+			log.Fatal(err)
+		}
 		fmt.Println(optimalOrder)
+		for _, v := range warehouse.PrecedenceViolations(optimalOrder, m) {
+			fmt.Printf("Warning: %v against the precedence rules.\n", v)
+		}
+		fmt.Printf("Start at %v and end at %v.\n", warehouse.LocationName(start), warehouse.LocationName(end))
 		fmt.Println("Here is the optimal path:")
-		s := warehouse.Route2String(optimalOrder, start, end, m)
+		s := warehouse.Route2String(optimalOrder, start, end, m, router)
 		fmt.Println(s)
-		fmt.Printf("Total distance traveled: %v\n", warehouse.RouteLength(optimalOrder, start, end, m, pathInfo))
+		if *textMap {
+			fmt.Print(warehouse.Route2Map(optimalOrder, start, end, m, router, *color))
+		}
+		if *directions {
+			fmt.Print(warehouse.Route2Directions(optimalOrder, start, end, m, router))
+		}
+		fmt.Printf("Total distance traveled: %v m\n", warehouse.RouteLength(optimalOrder, start, end, m, router))
+		fmt.Printf("Estimated time: %v s\n", warehouse.RouteDuration(optimalOrder, start, end, m, router, pathInfo, costModel))
 		if effort, missWeightData := warehouse.RouteEffort(optimalOrder, start, end, m, pathInfo); missWeightData {
 			fmt.Printf("There are some item(s) with no weight data, and the effort of this path is at least %v.\n", effort)
 		} else {
 			fmt.Printf("The effort is %v.\n", effort)
 		}
+		if *render != "" {
+			route := warehouse.Orders2Routes([]warehouse.Order{optimalOrder}, start, end, m, router)
+			warehouse.RenderMap(*render, []warehouse.RouteOrder{route}, depots, m)
+		}
 	} else if t == 2 {
 		fmt.Println("Please list file of orders to be processed:")
 		ordersPath := warehouse.ReadString()
-		orders := warehouse.ParesOrderInfo(ordersPath)
-		fmt.Println("Please list output file:")
+		// orders with time windows are sequenced by their cut-off
+		orders, info := warehouse.LoadOrders(ordersPath)
+		fmt.Println("Please list output file (- for none):")
 		outputPath := warehouse.ReadString()
+		fmt.Println("Please list pick list file, .csv or .xlsx (- for none):")
+		pickListPath := warehouse.ReadString()
+		if !strings.HasSuffix(pickListPath, ".csv") && !strings.HasSuffix(pickListPath, ".xlsx") && pickListPath != "-" {
+			log.Fatal("The pick list must be a .csv or an .xlsx file.")
+		}
 		fmt.Println("Computing...")
-		outputFile, err := os.Create(outputPath)
+
+		if merge && info != nil {
+			orders = warehouse.MergeOrdersByDue(orders, info, m, capacity)
+		} else if merge {
+			orders = warehouse.MergeOrders(orders, m, capacity)
+		}
+		planned := time.Now()
+		ros, err := warehouse.PlanBatches(orders, start, end, depots, m, router, costInfo, capacity, opt)
 		if err != nil {
 			log.Fatal(err)
 		}
-		defer outputFile.Close()
-
-		var reOrders [][]warehouse.Order
-		if weight > 0 {
-			orders = warehouse.MergeOrders(orders, m, weight)
-			for _, o := range orders {
-				reOrders = append(reOrders, warehouse.SplitOrder(o, m, weight))
-			}
-		} else {
-			for i,o := range orders {
-				reOrders[i] = []warehouse.Order{o}
+		meta := planMetadata(op, objective, planned)
+		for _, ro := range ros {
+			for _, o := range ro.Orders {
+				for _, v := range warehouse.PrecedenceViolations(o, m) {
+					fmt.Printf("Warning: %v against the precedence rules.\n", v)
+				}
 			}
 		}
-
-		var optimized [][]warehouse.Order
-		for _, reOs := range reOrders {
-			var ods []warehouse.Order
-			for _, order := range reOs {
-				var result warehouse.Order
-				if op == 0 {
-					result = warehouse.NNIOrderOptimizer(order, start, end, m, pathInfo)
-				} else {
-					result = warehouse.BnBOrderOptimizer(order, start, end, m, pathInfo, timeLimit)
+		var lateness []warehouse.Lateness
+		if info != nil {
+			ros, lateness = warehouse.SequenceBatches(ros, info, m, router, pathInfo, costModel)
+			late := 0
+			for _, l := range lateness {
+				if l.Late() {
+					fmt.Printf("Late: %v\n", l)
+					late++
 				}
-				ods = append(ods, result)
 			}
-			optimized = append(optimized, ods)
+			fmt.Printf("%v of %v orders will be late.\n", late, len(lateness))
+		}
+		for _, ro := range ros {
+			for i, o := range ro.Orders {
+				meta.LowerBound += warehouse.LowerBound(o, ro.Starts[i], ro.Ends[i], m, pathInfo)
+			}
+		}
+		plan := warehouse.NewPlan(ros, m, router, pathInfo, meta)
+		plan.Lateness = lateness
+		if outputPath != "-" {
+			writeJSON(outputPath, plan)
+		}
+		if strings.HasSuffix(pickListPath, ".csv") {
+			warehouse.WritePickListCSV(pickListPath, warehouse.PickList(plan))
+		} else if strings.HasSuffix(pickListPath, ".xlsx") {
+			warehouse.WritePickListXLSX(pickListPath, warehouse.PickList(plan))
+		}
+		if *render != "" {
+			warehouse.RenderMap(*render, ros, depots, m)
 		}
+	} else if t == 3 {
+		fmt.Println("Please list file of workers:")
+		workers, err := warehouse.LoadWorkers(warehouse.ReadString())
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Println("Please list file of orders to be processed:")
+		orders := warehouse.ParesOrderInfo(warehouse.ReadString())
+		fmt.Println("Please list output file:")
+		outputPath := warehouse.ReadString()
+		fmt.Println("Type 0 to minimize the longest shift, type 1 to minimize the total distance.")
+		obj := warehouse.FleetObjective(readInt())
+		fmt.Println("Computing...")
 
-		var ros []warehouse.RouteOrder
-		for _, o := range optimized {
-			ros = append(ros, warehouse.Orders2Routes(o, start, end, m))
+		planned := time.Now()
+		fleetCapacity := warehouse.FleetCapacity(workers)
+		// without merging every order keeps its own batches, as in the other modes
+		if merge {
+			orders = warehouse.MergeOrders(orders, m, fleetCapacity)
+		}
+		var batches []warehouse.Order
+		for _, o := range orders {
+			batches = append(batches, warehouse.SplitOrderByRoute(o, start, end, m, costInfo, fleetCapacity)...)
+		}
+		plans, unassigned := warehouse.PlanFleet(batches, workers, m, router, costInfo, opt, obj)
+		meta := planMetadata(op, objective, planned)
+		for _, p := range plans {
+			fmt.Printf("Worker %v: %v batch(es), total cost %v\n", p.Worker.ID, len(p.Batches), p.Length)
+		}
+		for _, o := range unassigned {
+			fmt.Printf("No worker can take the batch %v\n", o)
+		}
+		var routes []warehouse.RouteOrder
+		for _, p := range plans {
+			routes = append(routes, p.Route)
 		}
-		if rosB, err := json.Marshal(ros); err != nil {
-			log.Fatalln("error marshalling:", err)
+		stats := warehouse.Simulate(routes, m, router, warehouse.LoadSimConfig(simPath, costModel))
+		fmt.Println("Simulated with congestion:")
+		for i, st := range stats {
+			fmt.Printf("Worker %v: done after %v s, waited %v s, blocked %v time(s), %v deadlock(s)\n",
+				plans[i].Worker.ID, st.Completion, st.Waiting, st.Blocked, st.Deadlocks)
+		}
+		writeJSON(outputPath, warehouse.NewFleetPlan(plans, m, router, pathInfo, meta))
+	} else if t == 4 {
+		fmt.Println("Please list file of orders to be processed:")
+		orders := warehouse.ParesOrderInfo(warehouse.ReadString())
+		fmt.Println("Please list output file:")
+		outputPath := warehouse.ReadString()
+		fmt.Println("Computing...")
+		planned := time.Now()
+		routes, flows, err := warehouse.PlanZones(orders, m, router, costInfo, opt)
+		if err != nil {
+			log.Fatal(err)
+		}
+		meta := planMetadata(op, objective, planned)
+		for _, f := range flows {
+			fmt.Printf("Order %v: %v\n", f.OrderID, strings.Join(f.Zones, " -> "))
+		}
+		writeJSON(outputPath, warehouse.NewZonePlan(routes, flows, m, router, pathInfo, meta))
+	} else if t == 5 {
+		fmt.Println("Please list file of the route in progress:")
+		progressPath := warehouse.ReadString()
+		data, err := ioutil.ReadFile(progressPath)
+		if err != nil {
+			log.Fatal(err)
+		}
+		progress, err := warehouse.DecodeRouteProgress(data, warehouse.CurrentLayout())
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Println("Please list output file for the updated route:")
+		outputPath := warehouse.ReadString()
+		if outputPath == progressPath {
+			log.Fatal("The updated route must not overwrite the route in progress.")
+		}
+		fmt.Println("What items would you like to add? (separate by space)")
+		items := warehouse.ReadOrder(m)[0]
+		// the new items join the order being picked
+		current := append(append(warehouse.Order{}, progress.Done...), progress.Remaining...)
+		if len(current) > 0 {
+			for i := range items {
+				items[i].OrderID = current[0].OrderID
+			}
+		}
+		progress, err = warehouse.UpdateRoute(progress, items, m, costInfo, opt)
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Println("Here is the updated picking order:")
+		fmt.Println(progress.Remaining)
+		fmt.Println(warehouse.Route2String(progress.Remaining, progress.Pos, progress.End, m, router))
+		writeJSON(outputPath, progress)
+	} else if t == 6 {
+		fmt.Println("Please list file of the order stream, one JSON order per line (- for stdin):")
+		streamPath := warehouse.ReadString()
+		fmt.Println("Please list output file, one JSON wave per line (- for stdout):")
+		outputPath := warehouse.ReadString()
+		out := os.Stdout
+		if outputPath != "-" {
+			out, err = os.OpenFile(outputPath, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0666)
+			if err != nil {
+				log.Fatal(err)
+			}
+			defer out.Close()
+		}
+		orders := make(chan warehouse.Order)
+		if streamPath == "-" {
+			go warehouse.ReadOrderStream(os.Stdin, m, orders)
 		} else {
-			if err := ioutil.WriteFile(outputPath, rosB, 0777); err != nil {
-				log.Fatalln("error writing results to json:", err)
+			go warehouse.TailOrderStream(streamPath, time.Second, m, orders)
+		}
+		encoder := json.NewEncoder(out)
+		plan := func(wave []warehouse.Order) []warehouse.RouteOrder {
+			batches := wave
+			if merge {
+				batches = warehouse.MergeOrders(wave, m, capacity)
+			}
+			ros, err := warehouse.PlanBatches(batches, start, end, depots, m, router, costInfo, capacity, opt)
+			if err != nil {
+				log.Fatal(err)
+			}
+			return ros
+		}
+		warehouse.PlanWaves(orders, warehouse.LoadWaveConfig(wavesPath), plan, func(w warehouse.Wave) {
+			log.Printf("Wave %v: %v orders in %v batches.", w.Number, w.Orders, len(w.Routes))
+			meta := planMetadata(op, objective, w.Released)
+			meta.Wave = w.Number
+			if err := encoder.Encode(warehouse.NewPlan(w.Routes, m, router, pathInfo, meta)); err != nil {
+				log.Fatal(err)
 			}
+		})
+	} else if t == 7 {
+		fmt.Println("Please list file of the saved plan:")
+		plan := warehouse.LoadPlan(warehouse.ReadString())
+		var planned, now float64
+		for _, e := range warehouse.EvaluatePlan(plan, m, router, pathInfo) {
+			fmt.Printf("Trip %v: planned %v m, now %v m, effort %v\n", e.Trip, e.Planned.Length, e.Length, e.Effort)
+			for _, d := range e.Drift {
+				fmt.Printf("  Drift: %v\n", d)
+			}
+			planned += e.Planned.Length
+			now += e.Length
 		}
+		fmt.Printf("Total: planned %v m, now %v m\n", planned, now)
+		fmt.Println("Please list output file for the plan optimized again:")
+		outputPath := warehouse.ReadString()
+		newPlan := warehouse.ReoptimizePlan(plan, m, router, pathInfo, costInfo, opt, planMetadata(op, objective, time.Now()))
+		fmt.Printf("Optimized again: %v m (%v m now with the saved sequence)\n", newPlan.Totals.Length, now)
+		writeJSON(outputPath, newPlan)
 	}
 
 	/*prod, ok := m[id]
@@ -161,3 +403,37 @@ func main() {
 	}*/
 	//fmt.Println(warehouse.BruteForceOrderOptimizer(warehouse.Order{4,123, 67}, warehouse.Point{0, 0}, warehouse.Point{4, 6}, warehouse.ParseProductInfo(gridPath)))
 }
+
+// planMetadata returns the metadata of a plan made with the optimizer op since started
+func planMetadata(op int, objective warehouse.Objective, started time.Time) warehouse.PlanMetadata {
+	meta := warehouse.PlanMetadata{
+		Algorithm: "bnb",
+		Objective: objective.String(),
+		Created:   started,
+		Runtime:   time.Since(started).Seconds(),
+	}
+	if op == 0 {
+		meta.Algorithm = "nni"
+	}
+	return meta
+}
+
+// readInt returns an int from stdin
+func readInt() int {
+	n, err := strconv.Atoi(warehouse.ReadString())
+	if err != nil {
+		log.Fatal(err)
+	}
+	return n
+}
+
+// writeJSON writes the JSON encoding of v to the file at path
+func writeJSON(path string, v interface{}) {
+	if b, err := json.Marshal(v); err != nil {
+		log.Fatalln("error marshalling:", err)
+	} else {
+		if err := ioutil.WriteFile(path, b, 0777); err != nil {
+			log.Fatalln("error writing results to json:", err)
+		}
+	}
+}
@@ -0,0 +1,358 @@
+//go:build grpc
+// +build grpc
+
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"net"
+	"time"
+	"warehouse-optimizer/warehouse"
+	pb "warehouse-optimizer/warehousepb"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var grpcAddr = flag.String("grpc", "", "serve the gRPC API on the address, e.g. :9090")
+
+func init() {
+	startGRPC = func(s *server) bool {
+		if *grpcAddr == "" {
+			return false
+		}
+		lis, err := net.Listen("tcp", *grpcAddr)
+		if err != nil {
+			log.Fatal(err)
+		}
+		log.Printf("Serving gRPC on %v", *grpcAddr)
+		go func() {
+			log.Fatal(serveGRPC(lis, s))
+		}()
+		return true
+	}
+}
+
+// grpcServer answers the Router service with the data of the HTTP server
+type grpcServer struct {
+	pb.UnimplementedRouterServer
+	s *server
+}
+
+// serveGRPC answers the Router service on lis until it fails.
+// Tests can pass an in-process listener such as bufconn.
+func serveGRPC(lis net.Listener, s *server) error {
+	g := grpc.NewServer()
+	pb.RegisterRouterServer(g, &grpcServer{s: s})
+	return g.Serve(lis)
+}
+
+// grpcError returns the status of an error of the routing
+func grpcError(err error) error {
+	if _, ok := err.(requestError); ok {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	return status.Error(codes.Internal, err.Error())
+}
+
+// grpcDeadline returns the deadline of the request, no later than the one of the call
+func grpcDeadline(ctx context.Context, limit float64) (time.Time, error) {
+	dl, err := deadline(limit)
+	if err != nil {
+		return dl, grpcError(err)
+	}
+	if d, ok := ctx.Deadline(); ok && d.Before(dl) {
+		dl = d
+	}
+	return dl, nil
+}
+
+// acquire waits for a free optimization slot until the context ends
+func (g *grpcServer) acquire(ctx context.Context) error {
+	select {
+	case g.s.slots <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return status.FromContextError(ctx.Err()).Err()
+	}
+}
+
+func (g *grpcServer) release() {
+	<-g.s.slots
+}
+
+// fromPoint returns the location of a point of a request, its name when given
+func fromPoint(p *pb.Point) *warehouse.Location {
+	if p == nil {
+		return nil
+	}
+	if p.Name != "" {
+		return &warehouse.Location{Name: p.Name}
+	}
+	return &warehouse.Location{Point: warehouse.Point{X: int(p.X), Y: int(p.Y), F: int(p.F)}}
+}
+
+// toPoint returns the point with its location name
+func toPoint(p warehouse.Point) *pb.Point {
+	return &pb.Point{X: int32(p.X), Y: int32(p.Y), F: int32(p.F), Name: warehouse.LocationName(p)}
+}
+
+// toPathPoint returns a cell of a path, without a name
+func toPathPoint(p warehouse.Point) *pb.Point {
+	return &pb.Point{X: int32(p.X), Y: int32(p.Y), F: int32(p.F)}
+}
+
+// fromOrder returns the order id and the item ids of an order,
+// the id being the one of its first item
+func fromOrder(o *pb.Order) (int, []int) {
+	var id int
+	var items []int
+	for i, item := range o.GetItems() {
+		if i == 0 {
+			id = int(item.OrderId)
+		}
+		items = append(items, int(item.ProdId))
+	}
+	return id, items
+}
+
+func toOrder(o warehouse.Order) *pb.Order {
+	var items []*pb.Item
+	for _, item := range o {
+		items = append(items, &pb.Item{ProdId: int32(item.ProdID), OrderId: int32(item.OrderID)})
+	}
+	return &pb.Order{Items: items}
+}
+
+func toRouteOrder(ro warehouse.RouteOrder, m map[int]warehouse.Product) *pb.RouteOrder {
+	res := &pb.RouteOrder{Start: toPoint(ro.Start), End: toPoint(ro.End)}
+	for i, o := range ro.Orders {
+		trip := &pb.Trip{Order: toOrder(o), Path: &pb.Path{}, Length: ro.Lengths[i]}
+		for _, item := range o {
+			p := m[item.ProdID]
+			trip.Products = append(trip.Products, &pb.Product{
+				Id:      int32(item.ProdID),
+				Pos:     toPoint(p.Pos),
+				Level:   int32(p.Level),
+				Class:   p.Class,
+				OrderId: int32(item.OrderID),
+			})
+		}
+		for _, p := range ro.Paths[i] {
+			trip.Path.Points = append(trip.Path.Points, toPathPoint(p))
+		}
+		start, end := ro.Start, ro.End
+		if i < len(ro.Starts) {
+			start, end = ro.Starts[i], ro.Ends[i]
+		}
+		trip.Start, trip.End = toPoint(start), toPoint(end)
+		res.Trips = append(res.Trips, trip)
+	}
+	return res
+}
+
+func toPlanPoint(p *warehouse.PlanPoint) *pb.Point {
+	if p == nil {
+		return nil
+	}
+	return &pb.Point{X: int32(p.X), Y: int32(p.Y), F: int32(p.Floor), Name: p.Name}
+}
+
+func toPlanTotals(t warehouse.PlanTotals) *pb.PlanTotals {
+	return &pb.PlanTotals{
+		Trips:         int32(t.Trips),
+		Items:         int32(t.Items),
+		Length:        t.Length,
+		Effort:        t.Effort,
+		Weight:        t.Weight,
+		MissingWeight: t.MissingWeight,
+	}
+}
+
+func toPlan(plan warehouse.Plan) *pb.Plan {
+	res := &pb.Plan{
+		Version: int32(plan.Version),
+		Metadata: &pb.PlanMetadata{
+			Algorithm:      plan.Metadata.Algorithm,
+			Objective:      plan.Metadata.Objective,
+			Created:        plan.Metadata.Created.Format(time.RFC3339Nano),
+			RuntimeSeconds: plan.Metadata.Runtime,
+			LowerBound:     plan.Metadata.LowerBound,
+		},
+		Totals: toPlanTotals(plan.Totals),
+	}
+	for _, trip := range plan.Trips {
+		t := &pb.PlanTrip{
+			Batch:  int32(trip.Batch),
+			Start:  toPlanPoint(&trip.Start),
+			End:    toPlanPoint(&trip.End),
+			Totals: toPlanTotals(trip.Totals),
+		}
+		for _, step := range trip.Steps {
+			s := &pb.PlanStep{
+				Type:     step.Type,
+				Length:   step.Length,
+				Sku:      int32(step.SKU),
+				OrderId:  int32(step.OrderID),
+				Location: toPlanPoint(step.Location),
+				Access:   toPlanPoint(step.Access),
+				Level:    int32(step.Level),
+				Weight:   step.Weight,
+			}
+			for i := range step.Path {
+				s.Path = append(s.Path, toPlanPoint(&step.Path[i]))
+			}
+			t.Steps = append(t.Steps, s)
+		}
+		res.Trips = append(res.Trips, t)
+	}
+	return res
+}
+
+func optimizerName(o pb.Optimizer) string {
+	if o == pb.Optimizer_OPTIMIZER_BNB {
+		return "bnb"
+	}
+	return "nni"
+}
+
+func objectiveName(o pb.Objective) string {
+	if o == pb.Objective_OBJECTIVE_TIME {
+		return "time"
+	}
+	return warehouse.MinCost.String()
+}
+
+func fromRouteRequest(req *pb.RouteRequest) routeRequest {
+	id, items := fromOrder(req.GetOrder())
+	return routeRequest{
+		OrderID:   id,
+		Items:     items,
+		Start:     fromPoint(req.GetStart()),
+		End:       fromPoint(req.GetEnd()),
+		Optimizer: optimizerName(req.GetOptimizer()),
+		Objective: objectiveName(req.GetObjective()),
+		TimeLimit: req.GetTimeLimit(),
+	}
+}
+
+func (g *grpcServer) toRouteResponse(res routeResponse) *pb.RouteResponse {
+	return &pb.RouteResponse{
+		Route:      toRouteOrder(res.Route, g.s.m),
+		Length:     res.Length,
+		Duration:   res.Duration,
+		Violations: res.Violations,
+		Directions: res.Directions,
+	}
+}
+
+// Route returns the route of one order
+func (g *grpcServer) Route(ctx context.Context, req *pb.RouteRequest) (*pb.RouteResponse, error) {
+	dl, err := grpcDeadline(ctx, req.GetTimeLimit())
+	if err != nil {
+		return nil, err
+	}
+	if err := g.acquire(ctx); err != nil {
+		return nil, err
+	}
+	defer g.release()
+	ctx, cancel := context.WithDeadline(ctx, dl)
+	defer cancel()
+	res, err := g.s.route(ctx, fromRouteRequest(req))
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	return g.toRouteResponse(res), nil
+}
+
+// RouteProgress streams every better Order found by Branch & Bound with its length
+// in meters and ends with the final route. Without start and end it runs from the
+// first configured depots. The search stops when the call ends.
+func (g *grpcServer) RouteProgress(req *pb.RouteRequest, stream pb.Router_RouteProgressServer) error {
+	dl, err := grpcDeadline(stream.Context(), req.GetTimeLimit())
+	if err != nil {
+		return err
+	}
+	if err := g.acquire(stream.Context()); err != nil {
+		return err
+	}
+	defer g.release()
+	r := fromRouteRequest(req)
+	o, err := g.s.order(r.OrderID, r.Items)
+	if err != nil {
+		return grpcError(err)
+	}
+	d, err := g.s.depotsOf(r.Start, r.End)
+	if err != nil {
+		return grpcError(err)
+	}
+	costInfo, err := g.s.costInfo(r.Objective)
+	if err != nil {
+		return grpcError(err)
+	}
+	start, end := d.Starts[0], d.Ends[0]
+	ctx, cancel := context.WithDeadline(stream.Context(), dl)
+	defer cancel()
+	var sendErr error
+	route := warehouse.BnBOrderOptimizerContext(ctx, o, start, end, g.s.m, costInfo,
+		func(better warehouse.Order, _ float64) {
+			if sendErr != nil {
+				return
+			}
+			length := warehouse.RouteLength(better, start, end, g.s.m, g.s.router)
+			if sendErr = stream.Send(&pb.Progress{Order: toOrder(better), Length: length}); sendErr != nil {
+				cancel()
+			}
+		})
+	if sendErr != nil {
+		return sendErr
+	}
+	if err := stream.Context().Err(); err != nil {
+		return status.FromContextError(err).Err()
+	}
+	res := g.s.routeResponse(route, start, end)
+	return stream.Send(&pb.Progress{
+		Order:  toOrder(route),
+		Length: res.Length,
+		Final:  true,
+		Route:  toRouteOrder(res.Route, g.s.m),
+	})
+}
+
+// Batch returns the routes of the orders merged into batches
+func (g *grpcServer) Batch(ctx context.Context, req *pb.BatchRequest) (*pb.BatchResponse, error) {
+	dl, err := grpcDeadline(ctx, req.GetTimeLimit())
+	if err != nil {
+		return nil, err
+	}
+	if err := g.acquire(ctx); err != nil {
+		return nil, err
+	}
+	defer g.release()
+	br := batchRequest{
+		Start:     fromPoint(req.GetStart()),
+		End:       fromPoint(req.GetEnd()),
+		Optimizer: optimizerName(req.GetOptimizer()),
+		Objective: objectiveName(req.GetObjective()),
+		TimeLimit: req.GetTimeLimit(),
+		Weight:    req.GetWeight(),
+	}
+	for _, o := range req.GetOrders() {
+		id, items := fromOrder(o)
+		br.Orders = append(br.Orders, warehouse.StreamOrder{OrderID: id, Items: items})
+	}
+	ctx, cancel := context.WithDeadline(ctx, dl)
+	defer cancel()
+	plan, err := g.s.batch(ctx, br)
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	res := &pb.BatchResponse{Plan: toPlan(plan)}
+	for _, ro := range plan.Routes(g.s.m, g.s.router) {
+		res.Routes = append(res.Routes, toRouteOrder(ro, g.s.m))
+	}
+	return res, nil
+}
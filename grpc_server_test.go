@@ -0,0 +1,143 @@
+//go:build grpc
+// +build grpc
+
+package main
+
+import (
+	"context"
+	"io"
+	"net"
+	"testing"
+	pb "warehouse-optimizer/warehousepb"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// testClient returns a client of the Router service of the test server, served in process
+func testClient(t *testing.T) pb.RouterClient {
+	t.Helper()
+	lis := bufconn.Listen(1 << 20)
+	go serveGRPC(lis, testServer(t))
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		conn.Close()
+		lis.Close()
+	})
+	return pb.NewRouterClient(conn)
+}
+
+func testOrder(id int32, items ...int32) *pb.Order {
+	o := &pb.Order{}
+	for _, item := range items {
+		o.Items = append(o.Items, &pb.Item{ProdId: item, OrderId: id})
+	}
+	return o
+}
+
+func TestGRPCRoute(t *testing.T) {
+	c := testClient(t)
+	res, err := c.Route(context.Background(), &pb.RouteRequest{Order: testOrder(7, 3, 1, 2), TimeLimit: 5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	trips := res.GetRoute().GetTrips()
+	if len(trips) != 1 || len(trips[0].GetProducts()) != 3 {
+		t.Errorf("trips %v, want one trip of 3 products", trips)
+	}
+	if res.GetLength() <= 0 || len(res.GetDirections()) == 0 {
+		t.Errorf("length %v and directions %v must be set", res.GetLength(), res.GetDirections())
+	}
+
+	_, err = c.Route(context.Background(), &pb.RouteRequest{Order: testOrder(7, 9)})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("unknown item: %v, want %v", err, codes.InvalidArgument)
+	}
+}
+
+func TestGRPCBatch(t *testing.T) {
+	c := testClient(t)
+	res, err := c.Batch(context.Background(), &pb.BatchRequest{Orders: []*pb.Order{testOrder(1, 1, 2), testOrder(2, 3)}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	items := 0
+	for _, ro := range res.GetRoutes() {
+		for _, trip := range ro.GetTrips() {
+			items += len(trip.GetProducts())
+		}
+	}
+	if items != 3 {
+		t.Errorf("routes hold %v items, want 3", items)
+	}
+	if res.GetPlan().GetTotals().GetItems() != 3 || len(res.GetPlan().GetTrips()) != len(res.GetRoutes()) {
+		t.Errorf("plan %v, want the 3 items of the routes", res.GetPlan())
+	}
+}
+
+func TestGRPCRouteProgress(t *testing.T) {
+	c := testClient(t)
+	req := &pb.RouteRequest{Order: testOrder(7, 3, 1, 2), Optimizer: pb.Optimizer_OPTIMIZER_BNB, TimeLimit: 5}
+	stream, err := c.RouteProgress(context.Background(), req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var steps []*pb.Progress
+	for {
+		p, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		steps = append(steps, p)
+	}
+	if len(steps) < 2 {
+		t.Fatalf("%v progress message(s), want the initial and the final one", len(steps))
+	}
+	last := steps[len(steps)-1]
+	if !last.GetFinal() || last.GetRoute() == nil {
+		t.Errorf("last message %v must be final and hold the route", last)
+	}
+	// every length is in meters, so the final one is the best streamed
+	for _, p := range steps[:len(steps)-1] {
+		if p.GetFinal() {
+			t.Errorf("message %v is final before the end", p)
+		}
+		if p.GetLength() < last.GetLength() {
+			t.Errorf("streamed length %v is shorter than the final %v", p.GetLength(), last.GetLength())
+		}
+	}
+	route, err := c.Route(context.Background(), &pb.RouteRequest{Order: req.Order, Start: last.GetRoute().GetStart(),
+		End: last.GetRoute().GetEnd(), Optimizer: pb.Optimizer_OPTIMIZER_BNB, TimeLimit: 5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if route.GetLength() != last.GetLength() {
+		t.Errorf("final length %v, Route says %v", last.GetLength(), route.GetLength())
+	}
+}
+
+func TestGRPCRouteProgressCanceled(t *testing.T) {
+	c := testClient(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	stream, err := c.RouteProgress(ctx, &pb.RouteRequest{Order: testOrder(7, 3, 1, 2)})
+	if err == nil {
+		_, err = stream.Recv()
+	}
+	if status.Code(err) != codes.Canceled {
+		t.Errorf("%v, want %v", err, codes.Canceled)
+	}
+}
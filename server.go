@@ -0,0 +1,468 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"warehouse-optimizer/warehouse"
+)
+
+const (
+	maxTimeLimit = 30.0    // seconds a request may take at most
+	maxBodySize  = 8 << 20 // bytes of a request body
+
+	readTimeout  = 10 * time.Second
+	writeTimeout = maxTimeLimit*time.Second + 10*time.Second // the longest request and its answer
+	idleTimeout  = 2 * time.Minute
+)
+
+// server answers routing requests over HTTP.
+// The grid, the products and the distances are loaded once and only read afterwards,
+// so requests are handled concurrently.
+type server struct {
+	layout    warehouse.Layout
+	m         map[int]warehouse.Product
+	router    *warehouse.Router
+	pathInfo  map[warehouse.Point]map[warehouse.Point]float64
+	costModel warehouse.CostModel
+	capacity  warehouse.Capacity
+	depots    warehouse.Depots
+	timeOnce  sync.Once
+	timeInfo  map[warehouse.Point]map[warehouse.Point]float64
+	slots     chan struct{}
+}
+
+// routeRequest asks for the route of one order. Start and End are
+// location names like "A01-X01" or objects of X, Y and F.
+// Without Start and End the route starts and ends at the best depots.
+type routeRequest struct {
+	OrderID    int
+	Items      []int
+	Start, End *warehouse.Location
+	Optimizer  string  // "nni" (default) or "bnb"
+	Objective  string  // "cost" (default, "distance" before) or "time"
+	TimeLimit  float64 // seconds, 0 for the server's limit
+}
+
+// routeResponse is the route of one order, walked from Start to End
+type routeResponse struct {
+	Route      warehouse.RouteOrder
+	Start, End warehouse.PlanPoint
+	Length     float64
+	Duration   float64
+	Violations []string
+	Directions []string
+}
+
+// batchRequest asks for the routes of orders merged into batches for the cart
+type batchRequest struct {
+	Orders     []warehouse.StreamOrder
+	Start, End *warehouse.Location
+	Optimizer  string
+	Objective  string
+	TimeLimit  float64
+	Weight     float64 // weight limit of the cart, 0 for the configured one
+}
+
+type productResponse struct {
+	ID       int
+	Pos      warehouse.Point
+	Location string
+	Level    int
+	Class    string
+}
+
+type errorResponse struct {
+	Error string
+}
+
+// requestError is an invalid request, answered with 400 Bad Request
+type requestError string
+
+func (e requestError) Error() string {
+	return string(e)
+}
+
+// startGRPC starts the gRPC API next to the HTTP API and reports whether it runs.
+// It is only set when built with -tags grpc.
+var startGRPC func(s *server) bool
+
+// newServer returns a server of the loaded data in the layout in use, which
+// names the locations of the requests. Without configured depots routes start
+// and end at (0, 0).
+func newServer(m map[int]warehouse.Product, router *warehouse.Router, pathInfo map[warehouse.Point]map[warehouse.Point]float64,
+	costModel warehouse.CostModel, capacity warehouse.Capacity, depots warehouse.Depots) *server {
+	if len(depots.Starts) == 0 {
+		depots.Starts = []warehouse.Point{{}}
+	}
+	if len(depots.Ends) == 0 {
+		depots.Ends = []warehouse.Point{{}}
+	}
+	return &server{
+		layout:    warehouse.CurrentLayout(),
+		m:         m,
+		router:    router,
+		pathInfo:  pathInfo,
+		costModel: costModel,
+		capacity:  capacity,
+		depots:    depots,
+		slots:     make(chan struct{}, runtime.NumCPU()),
+	}
+}
+
+// serve answers HTTP requests on addr until the server fails
+func serve(addr string, s *server) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/route", s.handleRoute)
+	mux.HandleFunc("/batch", s.handleBatch)
+	mux.HandleFunc("/products/", s.handleProduct)
+	srv := &http.Server{
+		Addr:              addr,
+		Handler:           mux,
+		ReadHeaderTimeout: readTimeout,
+		ReadTimeout:       readTimeout,
+		WriteTimeout:      writeTimeout,
+		IdleTimeout:       idleTimeout,
+	}
+	log.Printf("Serving on %v", addr)
+	log.Fatal(srv.ListenAndServe())
+}
+
+func writeResponse(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Println("error writing response:", err)
+	}
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeResponse(w, status, errorResponse{err.Error()})
+}
+
+// decode reads the JSON body of a POST request into v
+func decode(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("use POST"))
+		return false
+	}
+	d := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBodySize))
+	d.DisallowUnknownFields()
+	if err := d.Decode(v); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return false
+	}
+	return true
+}
+
+// costInfo returns the cost matrix of the objective, building the time matrix on first use
+func (s *server) costInfo(objective string) (map[warehouse.Point]map[warehouse.Point]float64, error) {
+	switch objective {
+	case "", "cost", "distance":
+		return s.pathInfo, nil
+	case "time":
+		s.timeOnce.Do(func() {
+			s.timeInfo = warehouse.BuildCostInfo(s.pathInfo, warehouse.MinDuration, s.costModel)
+		})
+		return s.timeInfo, nil
+	}
+	return nil, requestError(fmt.Sprintf("unknown objective %q", objective))
+}
+
+// optimizer returns the optimizer of the request. Every search by Branch & Bound
+// may take half of the time left before the deadline of ctx, and once ctx is done
+// orders are left as they are, so that an abandoned request frees its slot quickly.
+func optimizer(ctx context.Context, name string) (warehouse.Optimizer, error) {
+	switch name {
+	case "", "nni":
+		return func(o warehouse.Order, start, end warehouse.Point, m map[int]warehouse.Product,
+			pathInfo map[warehouse.Point]map[warehouse.Point]float64) warehouse.Order {
+			if ctx.Err() != nil {
+				return o
+			}
+			return warehouse.NNIOrderOptimizer(o, start, end, m, pathInfo)
+		}, nil
+	case "bnb":
+		return func(o warehouse.Order, start, end warehouse.Point, m map[int]warehouse.Product,
+			pathInfo map[warehouse.Point]map[warehouse.Point]float64) warehouse.Order {
+			if ctx.Err() != nil {
+				return o
+			}
+			search := ctx
+			if dl, ok := ctx.Deadline(); ok {
+				var cancel context.CancelFunc
+				search, cancel = context.WithTimeout(ctx, time.Until(dl)/2)
+				defer cancel()
+			}
+			return warehouse.BnBOrderOptimizerContext(search, o, start, end, m, pathInfo, nil)
+		}, nil
+	}
+	return nil, requestError(fmt.Sprintf("unknown optimizer %q", name))
+}
+
+// deadline returns the time the request must be answered by
+func deadline(limit float64) (time.Time, error) {
+	if limit < 0 {
+		return time.Time{}, requestError("time limit must not be negative")
+	}
+	if limit == 0 || limit > maxTimeLimit {
+		limit = maxTimeLimit
+	}
+	return time.Now().Add(time.Duration(limit * float64(time.Second))), nil
+}
+
+// order returns the Order of the item ids, checking every item exists
+func (s *server) order(id int, items []int) (warehouse.Order, error) {
+	if len(items) == 0 {
+		return nil, requestError(fmt.Sprintf("order %v has no items", id))
+	}
+	var o warehouse.Order
+	for _, pid := range items {
+		if _, ok := s.m[pid]; !ok {
+			return nil, requestError(fmt.Sprintf("item id %v not exist", pid))
+		}
+		o = append(o, warehouse.Item{ProdID: pid, OrderID: id})
+	}
+	return o, nil
+}
+
+// depotsOf returns the depots of the request: the given start and end, or the configured ones
+func (s *server) depotsOf(start, end *warehouse.Location) (warehouse.Depots, error) {
+	d := s.depots
+	for _, l := range []struct {
+		name   string
+		loc    *warehouse.Location
+		points *[]warehouse.Point
+	}{{"start", start, &d.Starts}, {"end", end, &d.Ends}} {
+		if l.loc == nil {
+			continue
+		}
+		p, err := s.layout.Locate(*l.loc)
+		if err != nil {
+			return d, requestError(fmt.Sprintf("%v: %v", l.name, err))
+		}
+		if _, ok := s.pathInfo[p]; !ok {
+			return d, requestError(fmt.Sprintf("%v %v is not in the aisles", l.name, s.layout.LocationName(p)))
+		}
+		*l.points = []warehouse.Point{p}
+	}
+	return d, nil
+}
+
+// run calls f before ctx is done and answers the request with its result.
+// The number of optimizations running at once is bounded; a request waits
+// for a free slot until ctx is done. A slot is freed only when f returns,
+// so a timed out request keeps it until its optimizers, which give up once
+// ctx is done, have stopped.
+func (s *server) run(ctx context.Context, w http.ResponseWriter, f func() (interface{}, error)) {
+	select {
+	case s.slots <- struct{}{}:
+	case <-ctx.Done():
+		writeError(w, http.StatusServiceUnavailable, fmt.Errorf("server busy"))
+		return
+	}
+	type result struct {
+		v   interface{}
+		err error
+	}
+	done := make(chan result, 1)
+	go func() {
+		defer func() { <-s.slots }()
+		// a failing optimization answers its own request instead of stopping the server
+		defer func() {
+			if p := recover(); p != nil {
+				log.Printf("Request failed: %v", p)
+				done <- result{err: fmt.Errorf("internal error")}
+			}
+		}()
+		v, err := f()
+		done <- result{v, err}
+	}()
+	select {
+	case res := <-done:
+		if _, ok := res.err.(requestError); ok {
+			writeError(w, http.StatusBadRequest, res.err)
+		} else if res.err != nil {
+			writeError(w, http.StatusInternalServerError, res.err)
+		} else {
+			writeResponse(w, http.StatusOK, res.v)
+		}
+	case <-ctx.Done():
+		writeError(w, http.StatusGatewayTimeout, fmt.Errorf("time limit exceeded"))
+	}
+}
+
+// route returns the route of one order, searching until ctx is done
+func (s *server) route(ctx context.Context, req routeRequest) (routeResponse, error) {
+	var res routeResponse
+	o, err := s.order(req.OrderID, req.Items)
+	if err != nil {
+		return res, err
+	}
+	d, err := s.depotsOf(req.Start, req.End)
+	if err != nil {
+		return res, err
+	}
+	costInfo, err := s.costInfo(req.Objective)
+	if err != nil {
+		return res, err
+	}
+	opt, err := optimizer(ctx, req.Optimizer)
+	if err != nil {
+		return res, err
+	}
+	route, start, end, err := warehouse.DepotOrderOptimizer(o, d, s.m, costInfo, opt)
+	if err != nil {
+		return res, requestError(err.Error())
+	}
+	return s.routeResponse(route, start, end), nil
+}
+
+// routeResponse returns the response of a sequenced order walked from start to end
+func (s *server) routeResponse(route warehouse.Order, start, end warehouse.Point) routeResponse {
+	res := routeResponse{
+		Route:    warehouse.Orders2RoutesDepots([]warehouse.Order{route}, []warehouse.Point{start}, []warehouse.Point{end}, s.m, s.router),
+		Start:    s.planPoint(start),
+		End:      s.planPoint(end),
+		Length:   warehouse.RouteLength(route, start, end, s.m, s.router),
+		Duration: warehouse.RouteDuration(route, start, end, s.m, s.router, s.pathInfo, s.costModel),
+	}
+	for _, v := range warehouse.PrecedenceViolations(route, s.m) {
+		res.Violations = append(res.Violations, v.String())
+	}
+	res.Directions = warehouse.RouteDirections(route, start, end, s.m, s.router)
+	return res
+}
+
+// planPoint returns the point with its location name
+func (s *server) planPoint(p warehouse.Point) warehouse.PlanPoint {
+	return warehouse.PlanPoint{X: p.X, Y: p.Y, Floor: p.F, Name: s.layout.LocationName(p)}
+}
+
+// batch returns the plan of the orders merged into batches, searching until ctx is done
+func (s *server) batch(ctx context.Context, req batchRequest) (warehouse.Plan, error) {
+	var plan warehouse.Plan
+	if len(req.Orders) == 0 {
+		return plan, requestError("no orders")
+	}
+	if req.Weight < 0 {
+		return plan, requestError("weight limit must not be negative")
+	}
+	started := time.Now()
+	var orders []warehouse.Order
+	seen := make(map[int]bool)
+	for _, so := range req.Orders {
+		if seen[so.OrderID] {
+			return plan, requestError(fmt.Sprintf("order id %v is listed twice", so.OrderID))
+		}
+		seen[so.OrderID] = true
+		o, err := s.order(so.OrderID, so.Items)
+		if err != nil {
+			return plan, err
+		}
+		orders = append(orders, o)
+	}
+	d, err := s.depotsOf(req.Start, req.End)
+	if err != nil {
+		return plan, err
+	}
+	costInfo, err := s.costInfo(req.Objective)
+	if err != nil {
+		return plan, err
+	}
+	for _, o := range orders {
+		if err := warehouse.CheckReachable(o, d, s.m, costInfo); err != nil {
+			return plan, requestError(err.Error())
+		}
+	}
+	opt, err := optimizer(ctx, req.Optimizer)
+	if err != nil {
+		return plan, err
+	}
+	c := s.capacity
+	if req.Weight > 0 {
+		c.Weight = req.Weight
+	}
+	batches := warehouse.MergeOrders(orders, s.m, c)
+	ros, err := warehouse.PlanBatches(batches, d.Starts[0], d.Ends[0], d, s.m, s.router, costInfo, c, opt)
+	if err != nil {
+		return plan, requestError(err.Error())
+	}
+	meta := warehouse.PlanMetadata{
+		Algorithm: req.Optimizer,
+		Objective: req.Objective,
+		Created:   started,
+		Runtime:   time.Since(started).Seconds(),
+	}
+	if meta.Algorithm == "" {
+		meta.Algorithm = "nni"
+	}
+	if meta.Objective == "" || meta.Objective == "distance" {
+		meta.Objective = warehouse.MinCost.String()
+	}
+	return warehouse.NewPlan(ros, s.m, s.router, s.pathInfo, meta), nil
+}
+
+// handleRoute answers POST /route with the route of one order
+func (s *server) handleRoute(w http.ResponseWriter, r *http.Request) {
+	var req routeRequest
+	if !decode(w, r, &req) {
+		return
+	}
+	dl, err := deadline(req.TimeLimit)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	ctx, cancel := context.WithDeadline(r.Context(), dl)
+	defer cancel()
+	s.run(ctx, w, func() (interface{}, error) {
+		return s.route(ctx, req)
+	})
+}
+
+// handleBatch answers POST /batch with the plan of the orders merged into batches
+func (s *server) handleBatch(w http.ResponseWriter, r *http.Request) {
+	var req batchRequest
+	if !decode(w, r, &req) {
+		return
+	}
+	dl, err := deadline(req.TimeLimit)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	ctx, cancel := context.WithDeadline(r.Context(), dl)
+	defer cancel()
+	s.run(ctx, w, func() (interface{}, error) {
+		return s.batch(ctx, req)
+	})
+}
+
+// handleProduct answers GET /products/{id} with the location of the product
+func (s *server) handleProduct(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", http.MethodGet)
+		writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("use GET"))
+		return
+	}
+	id, err := strconv.Atoi(strings.TrimPrefix(r.URL.Path, "/products/"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid item id"))
+		return
+	}
+	prod, ok := s.m[id]
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Errorf("item id %v not exist", id))
+		return
+	}
+	writeResponse(w, http.StatusOK, productResponse{id, prod.Pos, s.layout.SlotName(prod.Pos, prod.Level), prod.Level, prod.Class})
+}
@@ -0,0 +1,210 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+	"warehouse-optimizer/warehouse"
+)
+
+// testServer returns a server of a warehouse of 4 by 4 shelves holding products 1, 2 and 3
+func testServer(t *testing.T) *server {
+	t.Helper()
+	return testServerTravel(t, warehouse.TravelModel{})
+}
+
+// testServerTravel returns the test server walking under the travel model
+func testServerTravel(t *testing.T, tm warehouse.TravelModel) *server {
+	t.Helper()
+	l := warehouse.DefaultLayout
+	l.MaxX, l.MaxY = 8, 8
+	warehouse.SetLayout(l)
+	t.Cleanup(func() { warehouse.SetLayout(warehouse.DefaultLayout) })
+	path := filepath.Join(t.TempDir(), "grid.csv")
+	if err := os.WriteFile(path, []byte("1, 0, 0\n2, 1, 2\n3, 3, 3\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	m := warehouse.ParseProductInfo(path, nil)
+	router := warehouse.NewRouter(tm)
+	return newServer(m, router, warehouse.BuildPathInfo(path, router), warehouse.DefaultCostModel,
+		warehouse.DefaultCapacity, warehouse.Depots{})
+}
+
+// post sends body as JSON to the handler and returns the recorded response
+func post(t *testing.T, h http.HandlerFunc, target string, body interface{}) *httptest.ResponseRecorder {
+	t.Helper()
+	data, err := json.Marshal(body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := httptest.NewRecorder()
+	h(w, httptest.NewRequest(http.MethodPost, target, bytes.NewReader(data)))
+	return w
+}
+
+func TestHandleRoute(t *testing.T) {
+	s := testServer(t)
+	for _, opt := range []string{"nni", "bnb"} {
+		w := post(t, s.handleRoute, "/route", routeRequest{OrderID: 7, Items: []int{3, 1, 2}, Optimizer: opt, TimeLimit: 5})
+		if w.Code != http.StatusOK {
+			t.Fatalf("%v: status %v: %v", opt, w.Code, w.Body)
+		}
+		var res routeResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &res); err != nil {
+			t.Fatal(err)
+		}
+		if len(res.Route.Orders) != 1 || len(res.Route.Orders[0]) != 3 {
+			t.Errorf("%v: route %v, want one trip of 3 items", opt, res.Route.Orders)
+		}
+		if res.Length <= 0 || res.Duration <= 0 {
+			t.Errorf("%v: length %v and duration %v must be positive", opt, res.Length, res.Duration)
+		}
+		for _, p := range []warehouse.PlanPoint{res.Start, res.End} {
+			if want := s.layout.LocationName(p.Point()); p.Name == "" || p.Name != want {
+				t.Errorf("%v: depot %+v, want it named %q", opt, p, want)
+			}
+		}
+	}
+}
+
+func TestHandleRouteBadRequest(t *testing.T) {
+	s := testServer(t)
+	for name, req := range map[string]routeRequest{
+		"unknown item":      {Items: []int{9}},
+		"no items":          {},
+		"unknown optimizer": {Items: []int{1}, Optimizer: "ga"},
+		"start on a shelf":  {Items: []int{1}, Start: &warehouse.Location{Point: warehouse.Point{X: 1, Y: 1}}},
+		"unknown location":  {Items: []int{1}, Start: &warehouse.Location{Name: "Q01"}},
+		"off the grid":      {Items: []int{1}, End: &warehouse.Location{Name: "A09-X01"}},
+		"negative limit":    {Items: []int{1}, TimeLimit: -1},
+	} {
+		if w := post(t, s.handleRoute, "/route", req); w.Code != http.StatusBadRequest {
+			t.Errorf("%v: status %v, want %v", name, w.Code, http.StatusBadRequest)
+		}
+	}
+}
+
+func TestHandleRouteUnreachable(t *testing.T) {
+	// one-way aisles up and to the right: product 1 by the origin cannot be
+	// reached from the far corner
+	s := testServerTravel(t, warehouse.TravelModel{Factors: map[warehouse.Direction]float64{
+		warehouse.Left: math.Inf(1), warehouse.Down: math.Inf(1)}})
+	corner := &warehouse.Location{Point: warehouse.Point{X: 8, Y: 8}}
+	w := post(t, s.handleRoute, "/route", routeRequest{Items: []int{3, 1}, Start: corner, End: corner})
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("route: status %v, want %v: %v", w.Code, http.StatusBadRequest, w.Body)
+	}
+	w = post(t, s.handleBatch, "/batch", batchRequest{Orders: []warehouse.StreamOrder{{OrderID: 1, Items: []int{1}}},
+		Start: corner, End: corner})
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("batch: status %v, want %v: %v", w.Code, http.StatusBadRequest, w.Body)
+	}
+}
+
+func TestHandleBatch(t *testing.T) {
+	s := testServer(t)
+	req := batchRequest{Orders: []warehouse.StreamOrder{{OrderID: 1, Items: []int{1, 2}}, {OrderID: 2, Items: []int{3}}}}
+	w := post(t, s.handleBatch, "/batch", req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status %v: %v", w.Code, w.Body)
+	}
+	plan, err := warehouse.DecodePlan(w.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plan.Totals.Items != 3 || plan.Metadata.Algorithm != "nni" {
+		t.Errorf("plan of %v items by %q, want 3 items by nni", plan.Totals.Items, plan.Metadata.Algorithm)
+	}
+
+	req.Orders = append(req.Orders, warehouse.StreamOrder{OrderID: 2, Items: []int{1}})
+	if w := post(t, s.handleBatch, "/batch", req); w.Code != http.StatusBadRequest {
+		t.Errorf("order listed twice: status %v, want %v", w.Code, http.StatusBadRequest)
+	}
+}
+
+func TestHandleProduct(t *testing.T) {
+	s := testServer(t)
+	for target, want := range map[string]int{
+		"/products/2":  http.StatusOK,
+		"/products/9":  http.StatusNotFound,
+		"/products/ab": http.StatusBadRequest,
+	} {
+		w := httptest.NewRecorder()
+		s.handleProduct(w, httptest.NewRequest(http.MethodGet, target, nil))
+		if w.Code != want {
+			t.Errorf("%v: status %v, want %v", target, w.Code, want)
+		}
+	}
+	w := httptest.NewRecorder()
+	s.handleProduct(w, httptest.NewRequest(http.MethodGet, "/products/2", nil))
+	var res productResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &res); err != nil {
+		t.Fatal(err)
+	}
+	if res.ID != 2 || res.Pos != (warehouse.Point{X: 3, Y: 5}) {
+		t.Errorf("product %+v, want 2 at (3, 5)", res)
+	}
+}
+
+func TestRunTimeout(t *testing.T) {
+	s := testServer(t)
+	s.slots = make(chan struct{}, 1)
+	release := make(chan struct{})
+	finished := make(chan struct{})
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	w := httptest.NewRecorder()
+	s.run(ctx, w, func() (interface{}, error) {
+		<-release
+		defer close(finished)
+		return nil, nil
+	})
+	if w.Code != http.StatusGatewayTimeout {
+		t.Errorf("status %v, want %v", w.Code, http.StatusGatewayTimeout)
+	}
+
+	// the slot stays taken until the timed out work returns, so new work is refused
+	ctx2, cancel2 := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel2()
+	w = httptest.NewRecorder()
+	s.run(ctx2, w, func() (interface{}, error) { return nil, nil })
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("busy: status %v, want %v", w.Code, http.StatusServiceUnavailable)
+	}
+
+	close(release)
+	<-finished
+	w = httptest.NewRecorder()
+	s.run(context.Background(), w, func() (interface{}, error) { return "ok", nil })
+	if w.Code != http.StatusOK {
+		t.Errorf("after release: status %v, want %v", w.Code, http.StatusOK)
+	}
+}
+
+func TestOptimizerStopsWithContext(t *testing.T) {
+	s := testServer(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	o := warehouse.Order{{ProdID: 3, OrderID: 1}, {ProdID: 1, OrderID: 1}, {ProdID: 2, OrderID: 1}}
+	for _, name := range []string{"nni", "bnb"} {
+		opt, err := optimizer(ctx, name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		start := time.Now()
+		got := opt(o, warehouse.Point{}, warehouse.Point{}, s.m, s.pathInfo)
+		if len(got) != len(o) {
+			t.Errorf("%v: order %v lost items of %v", name, got, o)
+		}
+		if d := time.Since(start); d > time.Second {
+			t.Errorf("%v: took %v after the context was done", name, d)
+		}
+	}
+}
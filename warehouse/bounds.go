@@ -29,7 +29,7 @@ func buildEdgeMatrix(o Order, start, end Point, m map[int]Product, pathInfo map[
 					src = FindDest(dest, prods[i]) // It will result in MST containing impossible edges
 					// Since always choosing the smaller one from the left/right of the shelf
 				}
-				length = pathInfo[src][dest]
+				length = pathInfo[src][dest] + reachCost(prods[j])
 				matrix[j][i] = length
 			} else {
 				matrix[j][i] = math.Inf(1)
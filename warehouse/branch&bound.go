@@ -2,6 +2,7 @@ package warehouse
 
 import (
 	"container/heap"
+	"context"
 	"math"
 	"time"
 )
@@ -170,7 +171,7 @@ func buildEdgeMatrixBnB(o Order, start, end Point, m map[int]Product, pathInfo m
 					src = FindDest(dest, prods[j]) // It will result in MST containing impossible edges
 					// Since always choosing the smaller one from the left/right of the shelf
 				}
-				length = pathInfo[src][dest]
+				length = pathInfo[src][dest] + reachCost(prods[i])
 				matrix[j][i] = length
 			} else {
 				matrix[j][i] = math.Inf(1)
@@ -204,7 +205,7 @@ func buildEdgeMatrixBnBLR(o Order, start, end Point, m map[int]Product, pathInfo
 				if !prods[(i+1)/2].pseudo {
 					dest.X += pm[i%2]
 				}
-				length = pathInfo[src][dest]
+				length = pathInfo[src][dest] + reachCost(prods[(i+1)/2])
 				matrix[j][i] = length
 			} else {
 				matrix[j][i] = math.Inf(1)
@@ -232,34 +233,46 @@ func BnBLROrderOptimizer(o Order, start, end Point, m map[int]Product, pathInfo
 	pq := priorityQueue{&initial}
 	heap.Init(&pq)
 	newOrder := NNIOrderOptimizer(o, start, end, m, pathInfo)
+	ruled := constrained(o, m)
 	min := math.Inf(1)
-	realMin := RouteLength(newOrder, start, end, m, pathInfo)
+	realMin := RouteCost(newOrder, start, end, m, pathInfo)
 	for pq.Len() > 0 {
 		if time.Since(t).Seconds() > timeLimit {
 			break
 		}
 		p := heap.Pop(&pq).(*vertex)
 		var v vertex
+		branched := false
 		remain := 0
 		if p.cost <= min {
+			var picked []int
+			if ruled {
+				for _, k := range p.path[1:] {
+					picked = append(picked, (k-1)/2)
+				}
+			}
 			for i := range p.matrix {
 				if math.IsInf(p.matrix[i][0], 1) {
 					continue
 				}
 				remain++
+				if ruled && !pickable(o, (i-1)/2, picked, m) {
+					continue
+				}
 				cv := checkNextLR(i, p, infSlice)
 				if cv.cost <= min {
 					heap.Push(&pq, &cv)
 				}
 				v = cv
+				branched = true
 			}
-			if remain == 2 && v.cost <= min {
+			if remain == 2 && branched && v.cost <= min {
 				min = v.cost
 				var tempOrder Order
 				for _, k := range v.path[1:] {
 					tempOrder = append(tempOrder, o[(k-1)/2])
 				}
-				tempOrderLen := RouteLength(tempOrder, start, end, m, pathInfo)
+				tempOrderLen := RouteCost(tempOrder, start, end, m, pathInfo)
 				if tempOrderLen < realMin {
 					realMin = tempOrderLen
 					newOrder = tempOrder
@@ -274,7 +287,20 @@ func BnBLROrderOptimizer(o Order, start, end Point, m map[int]Product, pathInfo
 
 // BnBOrderOptimizer Branch and Bound Order Optimizer
 func BnBOrderOptimizer(o Order, start, end Point, m map[int]Product, pathInfo map[Point]map[Point]float64, timeLimit float64) Order {
-	t := time.Now()
+	return BnBOrderOptimizerProgress(o, start, end, m, pathInfo, timeLimit, nil)
+}
+
+// BnBOrderOptimizerProgress is BnBOrderOptimizer calling improved with every better Order
+// found during the search and its route cost, starting with the initial one
+func BnBOrderOptimizerProgress(o Order, start, end Point, m map[int]Product, pathInfo map[Point]map[Point]float64, timeLimit float64, improved func(Order, float64)) Order {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(timeLimit*float64(time.Second)))
+	defer cancel()
+	return BnBOrderOptimizerContext(ctx, o, start, end, m, pathInfo, improved)
+}
+
+// BnBOrderOptimizerContext is BnBOrderOptimizerProgress searching until ctx is done,
+// e.g. when the caller gives up; improved may be nil
+func BnBOrderOptimizerContext(ctx context.Context, o Order, start, end Point, m map[int]Product, pathInfo map[Point]map[Point]float64, improved func(Order, float64)) Order {
 	matrix := buildEdgeMatrixBnB(o, start, end, m, pathInfo)
 	infSlice := make([]float64, len(matrix[0]))
 	for i := range infSlice {
@@ -290,37 +316,55 @@ func BnBOrderOptimizer(o Order, start, end Point, m map[int]Product, pathInfo ma
 	pq := priorityQueue{&initial}
 	heap.Init(&pq)
 	newOrder := NNIOrderOptimizer(o, start, end, m, pathInfo)
+	ruled := constrained(o, m)
 	min := reconstructCost(newOrder, o, start, end, m, pathInfo)
-	realMin := RouteLength(newOrder, start, end, m, pathInfo)
+	realMin := RouteCost(newOrder, start, end, m, pathInfo)
+	if improved != nil {
+		improved(newOrder, realMin)
+	}
 	for pq.Len() > 0 {
-		if time.Since(t).Seconds() > timeLimit {
+		if ctx.Err() != nil {
 			break
 		}
 		p := heap.Pop(&pq).(*vertex)
 		var v vertex
+		branched := false
 		remain := 0
 		if p.cost <= min {
+			var picked []int
+			if ruled {
+				for _, k := range p.path[1:] {
+					picked = append(picked, k-1)
+				}
+			}
 			for i := range p.matrix {
 				if math.IsInf(p.matrix[i][0], 1) {
 					continue
 				}
 				remain++
+				if ruled && !pickable(o, i-1, picked, m) {
+					continue
+				}
 				cv := checkNext(i, p, infSlice)
 				if cv.cost <= min {
 					heap.Push(&pq, &cv)
 				}
 				v = cv
+				branched = true
 			}
-			if remain == 1 && v.cost <= min {
+			if remain == 1 && branched && v.cost <= min {
 				min = v.cost
 				var tempOrder Order
 				for _, k := range v.path[1:] {
 					tempOrder = append(tempOrder, o[k-1])
 				}
-				tempOrderLen := RouteLength(tempOrder, start, end, m, pathInfo)
+				tempOrderLen := RouteCost(tempOrder, start, end, m, pathInfo)
 				if tempOrderLen < realMin {
 					realMin = tempOrderLen
 					newOrder = tempOrder
+					if improved != nil {
+						improved(newOrder, realMin)
+					}
 				}
 			}
 		} else {
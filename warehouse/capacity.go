@@ -0,0 +1,78 @@
+package warehouse
+
+import (
+	"log"
+)
+
+// Capacity defines the limits of a cart, or the load put on it.
+// A zero limit means the dimension is not limited.
+type Capacity struct {
+	Weight       float64
+	Volume       float64
+	Items        int
+	Compartments int
+}
+
+// DefaultCapacity is the cart used when no capacity config is given
+var DefaultCapacity = Capacity{Items: maxItem}
+
+// LoadCapacity returns the cart capacity from a config file of
+// "weight|volume|items|compartments, value" lines
+func LoadCapacity(path string) Capacity {
+	records, ok := readConfig(path)
+	if !ok {
+		return DefaultCapacity
+	}
+	c := DefaultCapacity
+	for _, rec := range records {
+		switch rec[0] {
+		case "weight":
+			c.Weight = configFloat(rec, 1)
+		case "volume":
+			c.Volume = configFloat(rec, 1)
+		case "items":
+			c.Items = configInt(rec, 1)
+		case "compartments":
+			c.Compartments = configInt(rec, 1)
+		default:
+			log.Fatalf("Unknown capacity entry %q.", rec[0])
+		}
+	}
+	return c
+}
+
+// Add returns the sum of two loads
+func (c Capacity) Add(l Capacity) Capacity {
+	return Capacity{
+		Weight:       c.Weight + l.Weight,
+		Volume:       c.Volume + l.Volume,
+		Items:        c.Items + l.Items,
+		Compartments: c.Compartments + l.Compartments,
+	}
+}
+
+// Fits reports whether the load stays within every limit of the cart
+func (c Capacity) Fits(load Capacity) bool {
+	return (c.Weight <= 0 || load.Weight <= c.Weight) &&
+		(c.Volume <= 0 || load.Volume <= c.Volume) &&
+		(c.Items <= 0 || load.Items <= c.Items) &&
+		(c.Compartments <= 0 || load.Compartments <= c.Compartments)
+}
+
+// itemLoad returns the load of a single item, not counting its compartment
+func itemLoad(i Item, m map[int]Product) Capacity {
+	return Capacity{Weight: m[i.ProdID].w, Volume: m[i.ProdID].v, Items: 1}
+}
+
+// OrderLoad returns the load of an order.
+// Every distinct OrderID takes one compartment of the cart.
+func OrderLoad(o Order, m map[int]Product) Capacity {
+	var load Capacity
+	ids := make(map[int]bool)
+	for _, i := range o {
+		load = load.Add(itemLoad(i, m))
+		ids[i.OrderID] = true
+	}
+	load.Compartments = len(ids)
+	return load
+}
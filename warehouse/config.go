@@ -0,0 +1,70 @@
+package warehouse
+
+import (
+	"encoding/csv"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// readConfig returns the records of a comma separated config file.
+// Lines starting with '#' are comments and records may have any length.
+// A missing file is not an error: ok is false and the caller keeps its defaults.
+func readConfig(path string) ([][]string, bool) {
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, false
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer file.Close()
+	r := csv.NewReader(file)
+	r.Comment = '#'
+	r.FieldsPerRecord = -1
+	r.TrimLeadingSpace = true
+	records, err := r.ReadAll()
+	if err != nil {
+		log.Fatal(err)
+	}
+	for _, rec := range records {
+		for i := range rec {
+			rec[i] = strings.TrimSpace(rec[i])
+		}
+		rec[0] = strings.ToLower(rec[0])
+	}
+	return records, true
+}
+
+// configFloat returns the i-th field of a config record as float64
+func configFloat(rec []string, i int) float64 {
+	if i >= len(rec) {
+		log.Fatalf("Config entry %v is missing field #%v.", rec, i)
+	}
+	f, err := strconv.ParseFloat(rec[i], 64)
+	if err != nil {
+		log.Fatal(err)
+	}
+	return f
+}
+
+// configInt returns the i-th field of a config record as int
+func configInt(rec []string, i int) int {
+	if i >= len(rec) {
+		log.Fatalf("Config entry %v is missing field #%v.", rec, i)
+	}
+	n, err := strconv.Atoi(rec[i])
+	if err != nil {
+		log.Fatal(err)
+	}
+	return n
+}
+
+// configString returns the i-th field of a config record
+func configString(rec []string, i int) string {
+	if i >= len(rec) {
+		log.Fatalf("Config entry %v is missing field #%v.", rec, i)
+	}
+	return rec[i]
+}
@@ -0,0 +1,182 @@
+package warehouse
+
+import (
+	"log"
+)
+
+// CostModel defines how long a route takes, in seconds.
+// WalkSpeed is in distance per second, PickTime per pick stop, HandlingTime per unit picked,
+// and ReachTime per unit of the height of the item picked.
+// Reaching the level of a pick takes as long as walking its reach cost, the
+// LevelCost of the Layout per level.
+type CostModel struct {
+	WalkSpeed    float64
+	PickTime     float64
+	HandlingTime float64
+	ReachTime    float64
+}
+
+// DefaultCostModel is used when no cost model config is given
+var DefaultCostModel = CostModel{WalkSpeed: 1.0, PickTime: 5.0, HandlingTime: 2.0}
+
+// Objective selects what the optimizers minimize
+type Objective int
+
+const (
+	// MinCost minimizes the travel cost of the route: its length scaled by the
+	// direction factors, plus the turn penalties and the reach costs of the picks
+	MinCost Objective = iota
+	// MinDuration minimizes the time of the route, walking and pick stops
+	MinDuration
+)
+
+func (obj Objective) String() string {
+	if obj == MinDuration {
+		return "time"
+	}
+	return "cost"
+}
+
+// LoadCostModel returns the cost model from a config file of
+// "walk_speed|pick_time|handling_time|reach_time, value" lines.
+// Turns are charged by the turn penalty of the TravelModel.
+func LoadCostModel(path string) CostModel {
+	records, ok := readConfig(path)
+	if !ok {
+		return DefaultCostModel
+	}
+	cm := DefaultCostModel
+	for _, rec := range records {
+		switch rec[0] {
+		case "walk_speed":
+			cm.WalkSpeed = configFloat(rec, 1)
+		case "turn_penalty":
+			log.Fatal("Turn penalties are set in the travel config.")
+		case "pick_time":
+			cm.PickTime = configFloat(rec, 1)
+		case "handling_time":
+			cm.HandlingTime = configFloat(rec, 1)
+		case "reach_time":
+			cm.ReachTime = configFloat(rec, 1)
+		case "level_time":
+			log.Fatal("Shelf levels are charged by level_cost in the layout config.")
+		default:
+			log.Fatalf("Unknown cost model entry %q.", rec[0])
+		}
+	}
+	if cm.WalkSpeed <= 0 {
+		log.Fatal("Walk speed must be positive.")
+	}
+	return cm
+}
+
+// countTurns returns the number of changes of direction along the path
+func countTurns(path Path) int {
+	turns := 0
+	if len(path) < 2 {
+		return turns
+	}
+	var prev Point
+	for i := range path[1:] {
+		if path[i].F != path[i+1].F {
+			// riding to another floor, the heading starts over
+			prev = Point{}
+			continue
+		}
+		d := Point{X: sign(path[i+1].X - path[i].X), Y: sign(path[i+1].Y - path[i].Y)}
+		if d == (Point{}) {
+			continue
+		}
+		if prev != (Point{}) && d != prev {
+			turns++
+		}
+		prev = d
+	}
+	return turns
+}
+
+func sign(n int) int {
+	switch {
+	case n > 0:
+		return 1
+	case n < 0:
+		return -1
+	}
+	return 0
+}
+
+// routePath returns the whole path of the route for a specific Order
+func routePath(o Order, start, end Point, m map[int]Product, r *Router) Path {
+	var path Path
+	src := start
+	for _, prod := range o {
+		dest := FindDest(src, m[prod.ProdID])
+		path = append(path, r.FindPath(src, dest)...)
+		src = dest
+	}
+	return append(path, r.FindPath(src, end)...)
+}
+
+// PickDuration returns the time spent at the shelves picking the Order
+func PickDuration(o Order, start Point, m map[int]Product, cm CostModel) float64 {
+	var t float64
+	src := start
+	for i, prod := range o {
+		dest := FindDest(src, m[prod.ProdID])
+		if i == 0 || dest != src {
+			t += cm.PickTime
+		}
+		t += cm.HandlingTime + cm.ReachTime*m[prod.ProdID].h
+		src = dest
+	}
+	return t
+}
+
+// pickTurns returns the number of turns at the pick stops of the route. The travel
+// costs between points leave them out, as every path starts without a heading.
+func pickTurns(o Order, start, end Point, m map[int]Product, r *Router) int {
+	turns := countTurns(routePath(o, start, end, m, r))
+	src := start
+	for _, prod := range o {
+		dest := FindDest(src, m[prod.ProdID])
+		turns -= countTurns(r.FindPath(src, dest))
+		src = dest
+	}
+	return turns - countTurns(r.FindPath(src, end))
+}
+
+// RouteDuration returns the time in seconds to walk the route for a specific Order and pick it.
+// pathInfo must hold the travel costs built by BuildPathInfo with the Router, turn
+// penalties included; the turns at the pick stops are charged on top.
+func RouteDuration(o Order, start, end Point, m map[int]Product, r *Router, pathInfo map[Point]map[Point]float64, cm CostModel) float64 {
+	walk := RouteCost(o, start, end, m, pathInfo)
+	if r.Model.TurnPenalty != 0 {
+		walk += float64(pickTurns(o, start, end, m, r)) * r.Model.TurnPenalty
+	}
+	return walk/cm.WalkSpeed + PickDuration(o, start, m, cm)
+}
+
+// BuildCostInfo returns the cost between Points the optimizers should minimize.
+// For MinDuration every leg to another point ends at a pick stop and is charged
+// its PickTime on top of the walk. The time is measured as the distance walked
+// in it, so that it adds up with the reach costs of the picks; handling and
+// reaching the items takes the same time in any sequence and is left out, and so
+// are the turns at the pick stops, which depend on the legs before and after.
+func BuildCostInfo(pathInfo map[Point]map[Point]float64, obj Objective, cm CostModel) map[Point]map[Point]float64 {
+	if obj == MinCost {
+		return pathInfo
+	}
+	stop := cm.PickTime * cm.WalkSpeed
+	costInfo := make(map[Point]map[Point]float64)
+	for src, m2 := range pathInfo {
+		c2 := make(map[Point]float64)
+		for dest, cost := range m2 {
+			c2[dest] = cost
+			if dest != src {
+				c2[dest] += stop
+			}
+		}
+		costInfo[src] = c2
+	}
+	return costInfo
+}
@@ -0,0 +1,57 @@
+package warehouse
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTimeObjectiveSavesPickStops(t *testing.T) {
+	l := DefaultLayout
+	l.MaxX, l.MaxY = 8, 8
+	SetLayout(l)
+	t.Cleanup(func() { SetLayout(DefaultLayout) })
+	// products 1 and 2 face each other across the aisle at x = 4
+	m := ParseProductInfo(writeInput(t, "grid.csv", "0, 0, 0\n1, 1, 2\n2, 2, 2\n3, 3, 1\n"), nil)
+	r := NewRouter(TravelModel{})
+	pathInfo := BuildPathInfo("", r)
+	cm := CostModel{WalkSpeed: 1, PickTime: 5}
+	start, end := Point{2, 6, 0}, Point{6, 8, 0}
+	o := Order{{0, 1}, {1, 1}, {2, 1}, {3, 1}}
+
+	byCost := BruteForceOrderOptimizer(append(Order{}, o...), start, end, m, BuildCostInfo(pathInfo, MinCost, cm))
+	byTime := BruteForceOrderOptimizer(append(Order{}, o...), start, end, m, BuildCostInfo(pathInfo, MinDuration, cm))
+	if reflect.DeepEqual(byCost, byTime) {
+		t.Fatalf("both objectives sequence %v", byCost)
+	}
+	if c, d := RouteLength(byCost, start, end, m, r), RouteLength(byTime, start, end, m, r); c >= d {
+		t.Errorf("%v walks %v m, %v walks %v m: the cost objective must walk less", byCost, c, byTime, d)
+	}
+	if c, d := RouteDuration(byCost, start, end, m, r, pathInfo, cm), RouteDuration(byTime, start, end, m, r, pathInfo, cm); d >= c {
+		t.Errorf("%v takes %v s, %v takes %v s: the time objective must be faster", byCost, c, byTime, d)
+	}
+}
+
+func TestReachCostPerPick(t *testing.T) {
+	l := DefaultLayout
+	l.MaxX, l.MaxY = 8, 8
+	SetLayout(l)
+	t.Cleanup(func() { SetLayout(DefaultLayout) })
+	m := ParseProductInfo(writeInput(t, "grid.csv", "0, 0, 0\n1, 1, 1, 3\n2, 2, 1, 4\n"), nil)
+	pathInfo := BuildPathInfo("", NewRouter(TravelModel{}))
+	start, end := Point{0, 0, 0}, Point{8, 8, 0}
+	for _, o := range []Order{
+		{{0, 1}, {1, 1}, {2, 1}},
+		{{1, 1}, {0, 1}, {2, 1}},
+		{{2, 1}, {1, 1}, {0, 1}},
+	} {
+		SetLayout(l)
+		walk := RouteCost(o, start, end, m, pathInfo)
+		reach := l
+		reach.LevelCost = 2
+		SetLayout(reach)
+		// the levels 3 and 4 are reached once each, whatever comes between
+		if got, want := RouteCost(o, start, end, m, pathInfo), walk+7*2; got != want {
+			t.Errorf("RouteCost(%v) = %v, want %v", o, got, want)
+		}
+	}
+}
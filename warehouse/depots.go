@@ -0,0 +1,130 @@
+package warehouse
+
+import (
+	"fmt"
+	"log"
+	"math"
+)
+
+// Depots are the candidate start and end points of a trip, e.g. the pack stations
+type Depots struct {
+	Starts, Ends []Point
+}
+
+// LoadDepots returns the depots from a config file of "start|end, x, y[, floor]"
+// or "start|end, location" lines
+func LoadDepots(path string) Depots {
+	var d Depots
+	records, ok := readConfig(path)
+	if !ok {
+		return d
+	}
+	for _, rec := range records {
+		p, n, err := configPoint(rec, 1)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if n == 2 && len(rec) > 3 {
+			p.F = configInt(rec, 3)
+		}
+		if p.X*p.Y%2 == 1 {
+			log.Fatalf("Depot %v is on a shelf.", p)
+		}
+		switch rec[0] {
+		case "start":
+			d.Starts = append(d.Starts, p)
+		case "end":
+			d.Ends = append(d.Ends, p)
+		default:
+			log.Fatalf("Unknown depot entry %q.", rec[0])
+		}
+	}
+	return d
+}
+
+// From returns the depots together with the start and the end of a worker:
+// every trip starts and ends at whichever of them serves it best
+func (d Depots) From(start, end Point) Depots {
+	return Depots{appendPoint(d.Starts, start), appendPoint(d.Ends, end)}
+}
+
+func appendPoint(points []Point, p Point) []Point {
+	for _, q := range points {
+		if q == p {
+			return points
+		}
+	}
+	return append(append([]Point{}, points...), p)
+}
+
+// bestEnd returns the candidate end closing the sequenced order at the least cost,
+// the first one when none can be reached
+func bestEnd(o Order, start Point, ends []Point, m map[int]Product, pathInfo map[Point]map[Point]float64) (Point, float64) {
+	end := ends[0]
+	min := RouteCost(o, start, end, m, pathInfo)
+	for _, e := range ends[1:] {
+		if length := RouteCost(o, start, e, m, pathInfo); length < min {
+			min = length
+			end = e
+		}
+	}
+	return end, min
+}
+
+// CheckReachable returns an error naming the first item of o that no trip between
+// the depots can pick, e.g. behind a one-way aisle
+func CheckReachable(o Order, d Depots, m map[int]Product, pathInfo map[Point]map[Point]float64) error {
+	for _, item := range o {
+		reachable := false
+		for _, s := range d.Starts {
+			for _, e := range d.Ends {
+				if !math.IsInf(RouteCost(Order{item}, s, e, m, pathInfo), 1) {
+					reachable = true
+				}
+			}
+		}
+		if !reachable {
+			return fmt.Errorf("item id %v cannot be reached between the depots", item.ProdID)
+		}
+	}
+	return nil
+}
+
+// DepotOrderOptimizer returns the Order optimized by opt together with the start
+// and the end chosen among the candidate depots.
+// For every start the tour is optimized towards the nearest end first,
+// then again towards the end that best closes the tour.
+// It fails when the order cannot be walked between any of the depots.
+func DepotOrderOptimizer(o Order, d Depots, m map[int]Product, pathInfo map[Point]map[Point]float64, opt Optimizer) (Order, Point, Point, error) {
+	if len(d.Starts) == 0 || len(d.Ends) == 0 {
+		log.Fatal("Depots need a start and an end.")
+	}
+	if err := CheckReachable(o, d, m, pathInfo); err != nil {
+		return nil, Point{}, Point{}, err
+	}
+	var newOrder Order
+	var start, end Point
+	min := math.Inf(1)
+	for _, s := range d.Starts {
+		e := d.Ends[0]
+		for _, c := range d.Ends[1:] {
+			if pathInfo[s][c] < pathInfo[s][e] {
+				e = c
+			}
+		}
+		order := opt(o, s, e, m, pathInfo)
+		closing, _ := bestEnd(order, s, d.Ends, m, pathInfo)
+		if closing != e {
+			order = opt(o, s, closing, m, pathInfo)
+		}
+		e, length := bestEnd(order, s, d.Ends, m, pathInfo)
+		if length < min {
+			min = length
+			newOrder, start, end = order, s, e
+		}
+	}
+	if newOrder == nil {
+		return nil, start, end, fmt.Errorf("order %v cannot be walked between the depots", o)
+	}
+	return newOrder, start, end, nil
+}
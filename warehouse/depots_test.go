@@ -0,0 +1,69 @@
+package warehouse
+
+import (
+	"math"
+	"testing"
+)
+
+// nni is the Nearest Neighbor optimizer with all its iterations
+func nni(o Order, start, end Point, m map[int]Product, pathInfo map[Point]map[Point]float64) Order {
+	return NNIOrderOptimizer(o, start, end, m, pathInfo)
+}
+
+func TestDepotOrderOptimizerOneWay(t *testing.T) {
+	m := testProducts(t)
+	// every aisle is one-way up and to the right, so product 0 next to the
+	// origin cannot be reached from the far corner
+	r := NewRouter(TravelModel{Factors: map[Direction]float64{Left: math.Inf(1), Down: math.Inf(1)}})
+	pathInfo := BuildPathInfo("", r)
+	corner := Point{8, 8, 0}
+	d := Depots{Starts: []Point{corner}, Ends: []Point{corner}}
+	for _, o := range []Order{{{0, 1}}, {{2, 1}, {0, 1}}} {
+		if err := CheckReachable(o, d, m, pathInfo); err == nil {
+			t.Errorf("CheckReachable(%v) = nil, want an error", o)
+		}
+		if got, _, _, err := DepotOrderOptimizer(o, d, m, pathInfo, nni); err == nil {
+			t.Errorf("DepotOrderOptimizer(%v) = %v, want an error", o, got)
+		}
+	}
+
+	d.Starts = []Point{{0, 0, 0}}
+	o := Order{{2, 1}, {0, 1}}
+	got, start, end, err := DepotOrderOptimizer(o, d, m, pathInfo, nni)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != len(o) || start != d.Starts[0] || end != corner {
+		t.Errorf("route %v from %v to %v, want the 2 items from %v to %v", got, start, end, d.Starts[0], corner)
+	}
+	if cost := RouteCost(got, start, end, m, pathInfo); math.IsInf(cost, 1) {
+		t.Errorf("route %v costs %v", got, cost)
+	}
+}
+
+func TestRouteCostEmptyOrder(t *testing.T) {
+	testProducts(t)
+	pathInfo := BuildPathInfo("", NewRouter(TravelModel{}))
+	start, end := Point{0, 0, 0}, Point{2, 4, 0}
+	if got := RouteCost(nil, start, end, nil, pathInfo); got != pathInfo[start][end] || got == 0 {
+		t.Errorf("RouteCost of no items = %v, want the walk between the depots %v", got, pathInfo[start][end])
+	}
+}
+
+func TestDepotsFromKeepsConfiguredStarts(t *testing.T) {
+	m := testProducts(t)
+	pathInfo := BuildPathInfo("", NewRouter(TravelModel{}))
+	// the pack station next to product 2 starts its trip better than the worker
+	station := Point{6, 6, 0}
+	d := Depots{Starts: []Point{station}}.From(Point{0, 8, 0}, Point{0, 8, 0})
+	if len(d.Starts) != 2 || len(d.Ends) != 1 {
+		t.Fatalf("depots %+v, want the station and the worker's start", d)
+	}
+	_, start, _, err := DepotOrderOptimizer(Order{{2, 1}}, d, m, pathInfo, nni)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if start != station {
+		t.Errorf("start %v, want the configured %v", start, station)
+	}
+}
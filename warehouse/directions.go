@@ -0,0 +1,176 @@
+package warehouse
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Aisles run along Y at even X and cross-aisles along X at even Y. Aisles, bays
+// and cross-aisles are counted from 1 like in the pick list and the location names.
+
+// PlaceName returns where the point is for a worker, e.g. "aisle 3, bay 4 (A03-B04)"
+func PlaceName(p Point) string {
+	var s string
+	switch {
+	case p.X%2 == 0 && p.Y%2 == 1:
+		s = fmt.Sprintf("aisle %v, bay %v", aisleNumber(p.X), bayNumber(p.Y))
+	case p.X%2 == 0:
+		s = fmt.Sprintf("aisle %v at cross-aisle %v", aisleNumber(p.X), crossNumber(p.Y))
+	default:
+		s = fmt.Sprintf("cross-aisle %v", crossNumber(p.Y))
+	}
+	if layout.Floors > 1 {
+		s += fmt.Sprintf(" on floor %v", p.F)
+	}
+	return fmt.Sprintf("%v (%v)", s, LocationName(p))
+}
+
+// headingOf returns the unit step from a to b, as countTurns does
+func headingOf(a, b Point) Point {
+	return Point{X: sign(b.X - a.X), Y: sign(b.Y - a.Y)}
+}
+
+// turn returns "left", "right" or "around" to go from heading h to next, "" to go on
+func turn(h, next Point) string {
+	switch cross := h.X*next.Y - h.Y*next.X; {
+	case h == (Point{}) || h == next:
+		return ""
+	case cross > 0:
+		return "left"
+	case cross < 0:
+		return "right"
+	}
+	return "around"
+}
+
+// shelfSide returns "left" or "right", the side of the shelf at loc for a worker at
+// access heading h. Without a heading along the aisle the worker faces the higher bays.
+func shelfSide(h, loc, access Point) string {
+	if h.X != 0 || h.Y == 0 {
+		h = Point{Y: 1}
+	}
+	// the left of h is (-h.Y, h.X)
+	if sign(loc.X-access.X) == -h.Y {
+		return "left"
+	}
+	return "right"
+}
+
+// directions builds the instructions of a route
+type directions struct {
+	lines   []string
+	heading Point
+	run     Path // the straight walk not yet told
+}
+
+// walk follows the path, telling the turns and the straight walks
+func (d *directions) walk(path Path) {
+	for i := 1; i < len(path); i++ {
+		a, b := path[i-1], path[i]
+		if a.F != b.F {
+			d.flush()
+			d.lines = append(d.lines, fmt.Sprintf("take the %v to floor %v", connectorName(a), b.F))
+			d.heading = Point{}
+			continue
+		}
+		if a == b {
+			continue
+		}
+		h := headingOf(a, b)
+		if len(d.run) > 0 && h == headingOf(d.run[0], d.run[1]) {
+			d.run[1] = b
+			continue
+		}
+		d.flush()
+		switch t := turn(d.heading, h); {
+		case t == "around":
+			d.lines = append(d.lines, "turn around")
+		case t != "" && h.X != 0:
+			d.lines = append(d.lines, fmt.Sprintf("turn %v at cross-aisle %v", t, crossNumber(a.Y)))
+		case t != "":
+			d.lines = append(d.lines, fmt.Sprintf("turn %v into aisle %v", t, aisleNumber(a.X)))
+		}
+		d.heading = h
+		d.run = Path{a, b}
+	}
+}
+
+// flush tells the straight walk in progress
+func (d *directions) flush() {
+	if len(d.run) == 0 {
+		return
+	}
+	a, b := d.run[0], d.run[1]
+	to := fmt.Sprintf("bay %v", bayNumber(b.Y))
+	if b.Y%2 == 0 {
+		to = fmt.Sprintf("cross-aisle %v", crossNumber(b.Y))
+	}
+	switch {
+	case a.X != b.X:
+		d.lines = append(d.lines, fmt.Sprintf("walk along cross-aisle %v to aisle %v", crossNumber(a.Y), aisleNumber(b.X)))
+	case b.Y > a.Y:
+		d.lines = append(d.lines, fmt.Sprintf("walk up aisle %v to %v", aisleNumber(a.X), to))
+	default:
+		d.lines = append(d.lines, fmt.Sprintf("walk down aisle %v to %v", aisleNumber(a.X), to))
+	}
+	d.run = nil
+}
+
+func connectorName(p Point) string {
+	for _, c := range layout.Connectors {
+		if c.X == p.X && c.Y == p.Y && c.Name != "" {
+			return c.Name
+		}
+	}
+	return "lift"
+}
+
+// RouteDirections returns the walking directions of the route, one instruction
+// per line, e.g. "walk down aisle 7 to bay 4" and "pick 2x SKU 108 from the shelf
+// on your left (A06-B04R)", the shelf told by its location name. Repeated picks
+// of a SKU are told once with their quantity; the order of a pick is told when
+// the route serves several orders.
+func RouteDirections(order Order, start, end Point, m map[int]Product, r *Router) []string {
+	d := &directions{lines: []string{"start at " + PlaceName(start)}}
+	several := false
+	for _, item := range order {
+		several = several || item.OrderID != order[0].OrderID
+	}
+	pos := start
+	for i := 0; i < len(order); {
+		item := order[i]
+		prod := m[item.ProdID]
+		dest := FindDest(pos, prod)
+		d.walk(r.FindPath(pos, dest))
+		d.flush()
+		n := 1
+		for i+n < len(order) && order[i+n] == item {
+			n++
+		}
+		pick := fmt.Sprintf("pick %vx SKU %v", n, item.ProdID)
+		if several {
+			pick += fmt.Sprintf(" for order %v", item.OrderID)
+		}
+		pick += fmt.Sprintf(" from the shelf on your %v", shelfSide(d.heading, prod.Pos, dest))
+		if prod.Level > 0 {
+			pick += fmt.Sprintf(", level %v", prod.Level)
+		}
+		pick += fmt.Sprintf(" (%v)", ProductLocation(prod))
+		d.lines = append(d.lines, pick)
+		pos = dest
+		i += n
+	}
+	d.walk(r.FindPath(pos, end))
+	d.flush()
+	d.lines = append(d.lines, "drop off at "+PlaceName(end))
+	return d.lines
+}
+
+// Route2Directions returns the walking directions of the route as text, one numbered line per instruction
+func Route2Directions(order Order, start, end Point, m map[int]Product, r *Router) string {
+	var b strings.Builder
+	for i, line := range RouteDirections(order, start, end, m, r) {
+		fmt.Fprintf(&b, "%v. %v%v\n", i+1, strings.ToUpper(line[:1]), line[1:])
+	}
+	return b.String()
+}
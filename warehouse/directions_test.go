@@ -0,0 +1,70 @@
+package warehouse
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRouteDirections(t *testing.T) {
+	m := testProducts(t)
+	r := NewRouter(TravelModel{})
+	for _, tc := range []struct {
+		name       string
+		order      Order
+		start, end Point
+		want       []string
+	}{
+		{"L-shaped", Order{{1, 1}}, Point{0, 0, 0}, Point{2, 8, 0}, []string{
+			"start at aisle 1 at cross-aisle 1 (A01-X01)",
+			"walk along cross-aisle 1 to aisle 2",
+			"turn left into aisle 2",
+			"walk up aisle 2 to bay 3",
+			"pick 1x SKU 1 from the shelf on your right, level 1 (A02-B03R-L1)",
+			"walk up aisle 2 to cross-aisle 5",
+			"drop off at aisle 2 at cross-aisle 5 (A02-X05)",
+		}},
+		{"several orders and a round trip", Order{{0, 1}, {0, 1}, {2, 2}}, Point{0, 0, 0}, Point{0, 0, 0}, []string{
+			"start at aisle 1 at cross-aisle 1 (A01-X01)",
+			"walk up aisle 1 to bay 1",
+			"pick 2x SKU 0 for order 1 from the shelf on your right (A01-B01R)",
+			"walk up aisle 1 to cross-aisle 2",
+			"turn right at cross-aisle 2",
+			"walk along cross-aisle 2 to aisle 4",
+			"turn left into aisle 4",
+			"walk up aisle 4 to bay 4",
+			"pick 1x SKU 2 for order 2 from the shelf on your right (A04-B04R)",
+			"turn around",
+			"walk down aisle 4 to cross-aisle 4",
+			"turn right at cross-aisle 4",
+			"walk along cross-aisle 4 to aisle 1",
+			"turn left into aisle 1",
+			"walk down aisle 1 to cross-aisle 1",
+			"drop off at aisle 1 at cross-aisle 1 (A01-X01)",
+		}},
+		// walking down the aisle the shelf on the right of the aisle is on the left
+		{"walking down", Order{{0, 1}}, Point{0, 4, 0}, Point{0, 4, 0}, []string{
+			"start at aisle 1 at cross-aisle 3 (A01-X03)",
+			"walk down aisle 1 to bay 1",
+			"pick 1x SKU 0 from the shelf on your left (A01-B01R)",
+			"turn around",
+			"walk up aisle 1 to cross-aisle 3",
+			"drop off at aisle 1 at cross-aisle 3 (A01-X03)",
+		}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := RouteDirections(tc.order, tc.start, tc.end, m, r); !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("directions\n%v\nwant\n%v", strings.Join(got, "\n"), strings.Join(tc.want, "\n"))
+			}
+		})
+	}
+}
+
+func TestRoute2DirectionsNumbersTheLines(t *testing.T) {
+	m := testProducts(t)
+	got := Route2Directions(Order{{0, 1}}, Point{0, 4, 0}, Point{0, 4, 0}, m, NewRouter(TravelModel{}))
+	lines := strings.Split(strings.TrimSuffix(got, "\n"), "\n")
+	if len(lines) != 6 || lines[0] != "1. Start at aisle 1 at cross-aisle 3 (A01-X03)" || lines[5] != "6. Drop off at aisle 1 at cross-aisle 3 (A01-X03)" {
+		t.Errorf("directions\n%v", got)
+	}
+}
@@ -0,0 +1,234 @@
+package warehouse
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// Optimizer is the common signature of the order optimizers
+type Optimizer func(o Order, start, end Point, m map[int]Product, pathInfo map[Point]map[Point]float64) Order
+
+// Worker defines a picker with its depots, cart and shift length.
+// Shift is the max total route cost of the worker, in the units of
+// the pathInfo given to PlanFleet, 0 for no limit.
+type Worker struct {
+	ID         int
+	Start, End Point
+	Capacity   Capacity
+	Shift      float64
+}
+
+// WorkerPlan is the sequence of batches assigned to a worker
+type WorkerPlan struct {
+	Worker  Worker
+	Batches []Order
+	Route   RouteOrder
+	Length  float64
+}
+
+// FleetObjective selects what PlanFleet minimizes
+type FleetObjective int
+
+const (
+	// MinMakespan minimizes the longest total route of a worker
+	MinMakespan FleetObjective = iota
+	// MinTotalDistance minimizes the sum of the routes of all workers
+	MinTotalDistance
+)
+
+// LoadWorkers returns the workers from a csv file of
+// "id, startX, startY, endX, endY, shift[, weight, volume, items, compartments]";
+// start and end may also be given as location names, "id, start, end, shift...".
+// Carts without capacity columns get DefaultCapacity.
+func LoadWorkers(path string) ([]Worker, error) {
+	records, ok := readConfig(path)
+	if !ok {
+		return nil, fmt.Errorf("workers file %v not exist", path)
+	}
+	var workers []Worker
+	for _, rec := range records {
+		start, i, err := configPoint(rec, 1)
+		if err != nil {
+			return nil, err
+		}
+		end, j, err := configPoint(rec, 1+i)
+		if err != nil {
+			return nil, err
+		}
+		i += 1 + j
+		w := Worker{
+			ID:       configInt(rec, 0),
+			Start:    start,
+			End:      end,
+			Shift:    configFloat(rec, i),
+			Capacity: DefaultCapacity,
+		}
+		if len(rec) > i+1 {
+			w.Capacity = Capacity{
+				Weight:       configFloat(rec, i+1),
+				Volume:       configFloat(rec, i+2),
+				Items:        configInt(rec, i+3),
+				Compartments: configInt(rec, i+4),
+			}
+		}
+		workers = append(workers, w)
+	}
+	return workers, nil
+}
+
+// FleetCapacity returns the largest cart that fits in the cart of every worker,
+// so that batches built with it can be assigned to anyone
+func FleetCapacity(workers []Worker) Capacity {
+	var c Capacity
+	for i, w := range workers {
+		if i == 0 {
+			c = w.Capacity
+			continue
+		}
+		c.Weight = minLimit(c.Weight, w.Capacity.Weight)
+		c.Volume = minLimit(c.Volume, w.Capacity.Volume)
+		c.Items = int(minLimit(float64(c.Items), float64(w.Capacity.Items)))
+		c.Compartments = int(minLimit(float64(c.Compartments), float64(w.Capacity.Compartments)))
+	}
+	return c
+}
+
+// minLimit returns the tighter of two limits where 0 means no limit
+func minLimit(a, b float64) float64 {
+	if a <= 0 {
+		return b
+	}
+	if b <= 0 {
+		return a
+	}
+	return math.Min(a, b)
+}
+
+// fleetRoute is a batch optimized for the depots of a worker
+type fleetRoute struct {
+	order  Order
+	length float64
+}
+
+// PlanFleet assigns the batches to the workers and returns the plan of every
+// worker, and the batches no worker can take within its cart and shift.
+// Batches are placed greedily, longest first, then moved and swapped between
+// workers as long as the objective improves.
+func PlanFleet(batches []Order, workers []Worker, m map[int]Product, r *Router, pathInfo map[Point]map[Point]float64, opt Optimizer, obj FleetObjective) ([]WorkerPlan, []Order) {
+	// routes[w][b] is batch b optimized for worker w, shared by workers with the same depots
+	routes := make([][]fleetRoute, len(workers))
+	cache := make(map[[2]Point][]fleetRoute)
+	for w, worker := range workers {
+		depots := [2]Point{worker.Start, worker.End}
+		if _, ok := cache[depots]; !ok {
+			rs := make([]fleetRoute, len(batches))
+			for b, batch := range batches {
+				o := opt(batch, worker.Start, worker.End, m, pathInfo)
+				rs[b] = fleetRoute{o, RouteCost(o, worker.Start, worker.End, m, pathInfo)}
+			}
+			cache[depots] = rs
+		}
+		routes[w] = cache[depots]
+	}
+	canTake := func(w, b int, load float64) bool {
+		return workers[w].Capacity.Fits(OrderLoad(batches[b], m)) &&
+			(workers[w].Shift <= 0 || load+routes[w][b].length <= workers[w].Shift)
+	}
+
+	minLength := make([]float64, len(batches))
+	indices := make([]int, len(batches))
+	for b := range batches {
+		indices[b] = b
+		minLength[b] = math.Inf(1)
+		for w := range workers {
+			minLength[b] = math.Min(minLength[b], routes[w][b].length)
+		}
+	}
+	sort.SliceStable(indices, func(i, j int) bool { return minLength[indices[i]] > minLength[indices[j]] })
+
+	assigned := make([][]int, len(workers))
+	loads := make([]float64, len(workers))
+	var unassigned []Order
+	for _, b := range indices {
+		best := -1
+		bestCost := math.Inf(1)
+		for w := range workers {
+			if !canTake(w, b, loads[w]) {
+				continue
+			}
+			cost := routes[w][b].length
+			if obj == MinMakespan {
+				cost += loads[w]
+			}
+			if best < 0 || cost < bestCost || (cost == bestCost && loads[w] < loads[best]) {
+				best = w
+				bestCost = cost
+			}
+		}
+		if best < 0 {
+			unassigned = append(unassigned, batches[b])
+			continue
+		}
+		assigned[best] = append(assigned[best], b)
+		loads[best] += routes[best][b].length
+	}
+
+	// score returns the objective of the loads of two workers
+	score := func(a, b float64) float64 {
+		if obj == MinMakespan {
+			return math.Max(a, b)
+		}
+		return a + b
+	}
+	for improved := true; improved; {
+		improved = false
+		for w1 := range workers {
+			for w2 := range workers {
+				if w1 == w2 {
+					continue
+				}
+				for i := 0; i < len(assigned[w1]); i++ {
+					b := assigned[w1][i]
+					cur := score(loads[w1], loads[w2])
+					l1 := loads[w1] - routes[w1][b].length
+					// move b from w1 to w2
+					if canTake(w2, b, loads[w2]) && score(l1, loads[w2]+routes[w2][b].length) < cur-1e-9 {
+						assigned[w1] = append(assigned[w1][:i], assigned[w1][i+1:]...)
+						assigned[w2] = append(assigned[w2], b)
+						loads[w1] = l1
+						loads[w2] += routes[w2][b].length
+						improved = true
+						i--
+						continue
+					}
+					// swap b with a batch of w2
+					for k, c := range assigned[w2] {
+						l2 := loads[w2] - routes[w2][c].length
+						if canTake(w2, b, l2) && canTake(w1, c, l1) &&
+							score(l1+routes[w1][c].length, l2+routes[w2][b].length) < cur-1e-9 {
+							assigned[w1][i], assigned[w2][k] = c, b
+							loads[w1] = l1 + routes[w1][c].length
+							loads[w2] = l2 + routes[w2][b].length
+							improved = true
+							break
+						}
+					}
+				}
+			}
+		}
+	}
+
+	plans := make([]WorkerPlan, len(workers))
+	for w, worker := range workers {
+		var orders []Order
+		for _, b := range assigned[w] {
+			orders = append(orders, routes[w][b].order)
+		}
+		plans[w] = WorkerPlan{Worker: worker, Batches: orders, Length: loads[w]}
+		if len(orders) > 0 {
+			plans[w].Route = Orders2Routes(orders, worker.Start, worker.End, m, r)
+		}
+	}
+	return plans, unassigned
+}
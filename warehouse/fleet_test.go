@@ -0,0 +1,80 @@
+package warehouse
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// prodIDs returns the sorted ids of the products of the orders
+func prodIDs(orders []Order) []int {
+	ids := []int{}
+	for _, o := range orders {
+		for _, item := range o {
+			ids = append(ids, item.ProdID)
+		}
+	}
+	sort.Ints(ids)
+	return ids
+}
+
+func TestPlanFleet(t *testing.T) {
+	l := DefaultLayout
+	l.MaxX, l.MaxY = 8, 8
+	SetLayout(l)
+	t.Cleanup(func() { SetLayout(DefaultLayout) })
+	// product id is on the shelf (id%4, id/4); product 15 by the far corner weighs 5
+	var grid strings.Builder
+	for id := 0; id < 16; id++ {
+		fmt.Fprintf(&grid, "%v, %v, %v\n", id, id%4, id/4)
+	}
+	m := ParseProductInfo(writeInput(t, "grid.csv", grid.String()),
+		map[int][]float64{2: {1, 1, 1, 1}, 6: {1, 1, 1, 1}, 15: {1, 1, 1, 5}})
+	r := NewRouter(TravelModel{})
+	pathInfo := BuildPathInfo("", r)
+	// from the origin the batches cost 14, 26 and 10, from the far corner 14, 2 and 18
+	batches := []Order{{{6, 1}}, {{15, 2}}, {{2, 3}}}
+	corner := Point{8, 8, 0}
+	workers := func(c2 Capacity, shift float64) []Worker {
+		return []Worker{
+			{ID: 1, Capacity: DefaultCapacity, Shift: shift},
+			{ID: 2, Start: corner, End: corner, Capacity: c2, Shift: shift},
+		}
+	}
+	for _, tc := range []struct {
+		name       string
+		workers    []Worker
+		obj        FleetObjective
+		want       [2][]int
+		lengths    [2]float64
+		unassigned []int
+	}{
+		// placing the longest batch first gives 6 to the origin and 2 and 15 to the
+		// far corner, 20 long; swapping 6 and 2 shortens the longest route to 16
+		{"makespan", workers(DefaultCapacity, 0), MinMakespan, [2][]int{{2}, {6, 15}}, [2]float64{10, 16}, []int{}},
+		{"total distance", workers(DefaultCapacity, 0), MinTotalDistance, [2][]int{{2, 6}, {15}}, [2]float64{24, 2}, []int{}},
+		// product 15 does not fit on the far corner's cart
+		{"cart makespan", workers(Capacity{Weight: 3, Items: maxItem}, 0), MinMakespan, [2][]int{{15}, {2, 6}}, [2]float64{26, 32}, []int{}},
+		{"cart total distance", workers(Capacity{Weight: 3, Items: maxItem}, 0), MinTotalDistance, [2][]int{{2, 6, 15}, {}}, [2]float64{50, 0}, []int{}},
+		// no shift is long enough for product 6
+		{"shift", workers(DefaultCapacity, 10), MinMakespan, [2][]int{{2}, {15}}, [2]float64{10, 2}, []int{6}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			plans, unassigned := PlanFleet(batches, tc.workers, m, r, pathInfo, nni, tc.obj)
+			for w, plan := range plans {
+				if got := prodIDs(plan.Batches); !reflect.DeepEqual(got, tc.want[w]) || plan.Length != tc.lengths[w] {
+					t.Errorf("worker %v: products %v of length %v, want %v of length %v",
+						plan.Worker.ID, got, plan.Length, tc.want[w], tc.lengths[w])
+				}
+				if plan.Worker.ID != tc.workers[w].ID {
+					t.Errorf("plan %v is of worker %v", w, plan.Worker.ID)
+				}
+			}
+			if got := prodIDs(unassigned); !reflect.DeepEqual(got, tc.unassigned) {
+				t.Errorf("unassigned %v, want %v", got, tc.unassigned)
+			}
+		})
+	}
+}
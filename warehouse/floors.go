@@ -0,0 +1,90 @@
+package warehouse
+
+import (
+	"math"
+)
+
+// Connector links floors through a lift or stairs standing at the same X, Y
+// on every floor it serves. Cost is charged in meters per floor traversed
+// and Capacity is the number of pickers it carries at once (0 for no limit).
+type Connector struct {
+	Name     string
+	X, Y     int
+	Floors   []int
+	Cost     float64
+	Capacity int
+}
+
+// At returns the point of the connector on floor f
+func (c Connector) At(f int) Point {
+	return Point{X: c.X, Y: c.Y, F: f}
+}
+
+// Serves reports whether the connector stops at floor f
+func (c Connector) Serves(f int) bool {
+	for _, floor := range c.Floors {
+		if floor == f {
+			return true
+		}
+	}
+	return false
+}
+
+// connectorBetween returns the connector ridden from src to dest on another floor
+func connectorBetween(src, dest Point) (Connector, bool) {
+	for _, c := range layout.Connectors {
+		if c.X == src.X && c.Y == src.Y && src.X == dest.X && src.Y == dest.Y && c.Serves(src.F) && c.Serves(dest.F) {
+			return c, true
+		}
+	}
+	return Connector{}, false
+}
+
+// rideCost returns the cost of riding a connector from src to dest on another floor,
+// +Inf if no connector links them
+func rideCost(src, dest Point) float64 {
+	c, ok := connectorBetween(src, dest)
+	if !ok {
+		return math.Inf(1)
+	}
+	return c.Cost * math.Abs(float64(dest.F-src.F))
+}
+
+// findPathFloors returns the cheapest path between points on different floors
+// through one connector serving both floors, nil if there is none
+func (r *Router) findPathFloors(src, dest Point) Path {
+	var best Path
+	min := math.Inf(1)
+	for _, c := range layout.Connectors {
+		if !c.Serves(src.F) || !c.Serves(dest.F) {
+			continue
+		}
+		path := r.FindPath(src, c.At(src.F))
+		if len(path) == 0 {
+			path = Path{src}
+		}
+		path = append(path, c.At(dest.F))
+		if out := r.FindPath(c.At(dest.F), dest); len(out) > 1 {
+			path = append(path, out[1:]...)
+		}
+		if cost := r.PathCost(path); cost < min {
+			min = cost
+			best = path
+		}
+	}
+	return best
+}
+
+// crossFloorCost returns the travel cost between points on different floors
+// from the costs within the floors in pathInfo
+func crossFloorCost(pathInfo map[Point]map[Point]float64, src, dest Point) float64 {
+	min := math.Inf(1)
+	for _, c := range layout.Connectors {
+		if !c.Serves(src.F) || !c.Serves(dest.F) {
+			continue
+		}
+		cost := pathInfo[src][c.At(src.F)] + c.Cost*math.Abs(float64(dest.F-src.F)) + pathInfo[c.At(dest.F)][dest]
+		min = math.Min(min, cost)
+	}
+	return min
+}
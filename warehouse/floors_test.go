@@ -0,0 +1,70 @@
+package warehouse
+
+import (
+	"math"
+	"testing"
+)
+
+func TestFindPathFloors(t *testing.T) {
+	l := DefaultLayout
+	l.MaxX, l.MaxY = 8, 8
+	// floor 3 is served by no connector
+	l.Floors = 4
+	l.Connectors = []Connector{
+		{Name: "lift", X: 0, Y: 0, Floors: []int{0, 1, 2}, Cost: 3},
+		{Name: "stairs", X: 8, Y: 8, Floors: []int{0, 1}, Cost: 1},
+	}
+	SetLayout(l)
+	t.Cleanup(func() { SetLayout(DefaultLayout) })
+	r := NewRouter(TravelModel{})
+	pathInfo := BuildPathInfo("", r)
+	for _, tc := range []struct {
+		name      string
+		src, dest Point
+		via       Point // the connector on the floor of src
+		cost      float64
+	}{
+		{"lift by the start", Point{2, 0, 0}, Point{2, 0, 1}, Point{0, 0, 0}, 2 + 3 + 2},
+		{"stairs by the start", Point{6, 8, 0}, Point{6, 8, 1}, Point{8, 8, 0}, 2 + 1 + 2},
+		{"stairs down", Point{6, 8, 1}, Point{8, 6, 0}, Point{8, 8, 1}, 2 + 1 + 2},
+		// the stairs do not reach floor 2
+		{"lift two floors", Point{6, 8, 0}, Point{6, 8, 2}, Point{0, 0, 0}, 14 + 2*3 + 14},
+		{"in the lift", Point{0, 0, 2}, Point{0, 0, 0}, Point{0, 0, 2}, 2 * 3},
+		{"unserved floor", Point{2, 0, 0}, Point{2, 0, 3}, Point{}, math.Inf(1)},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			path := r.FindPath(tc.src, tc.dest)
+			if got := crossFloorCost(pathInfo, tc.src, tc.dest); got != tc.cost {
+				t.Errorf("crossFloorCost %v, want %v", got, tc.cost)
+			}
+			if got := pathInfo[tc.src][tc.dest]; got != tc.cost {
+				t.Errorf("path info %v, want %v", got, tc.cost)
+			}
+			if math.IsInf(tc.cost, 1) {
+				if path != nil {
+					t.Errorf("path %v, want none", path)
+				}
+				return
+			}
+			if len(path) < 2 || path[0] != tc.src || path[len(path)-1] != tc.dest {
+				t.Fatalf("path %v does not lead from %v to %v", path, tc.src, tc.dest)
+			}
+			if got := r.PathCost(path); got != tc.cost {
+				t.Errorf("path %v costs %v, want %v", path, got, tc.cost)
+			}
+			// the floor changes once, riding the connector at via
+			rides := 0
+			for i := range path[1:] {
+				if a, b := path[i], path[i+1]; a.F != b.F {
+					rides++
+					if a != tc.via || b != (Point{tc.via.X, tc.via.Y, tc.dest.F}) {
+						t.Errorf("path %v rides from %v to %v, want from %v", path, a, b, tc.via)
+					}
+				}
+			}
+			if rides != 1 {
+				t.Errorf("path %v changes floor %v times, want once", path, rides)
+			}
+		})
+	}
+}
@@ -0,0 +1,463 @@
+package warehouse
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"io/ioutil"
+	"log"
+	"math"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Order and product files are read in whatever format they come: a JSON array or
+// JSON Lines of records, or a text file separated by tabs, commas or semicolons
+// whose columns are found by the names of its header when it has one.
+
+// Formats of the input files
+const (
+	formatJSON      = "json"
+	formatJSONL     = "jsonl"
+	formatDelimited = "delimited"
+)
+
+// OrderRecord is an order of a JSON or JSON Lines order file. Records sharing an
+// OrderID are one order; those without one are numbered as in mergeOrders. An item
+// listed n times is picked n times; Release, Due and Priority are as in OrderInfo.
+// Fields are named in snake_case like the plan; "OrderID" is read as well.
+type OrderRecord struct {
+	OrderID  int     `json:"order_id"`
+	Items    []int   `json:"items"`
+	Release  float64 `json:"release,omitempty"`
+	Due      float64 `json:"due,omitempty"`
+	Priority int     `json:"priority,omitempty"`
+	hasID    bool
+}
+
+// UnmarshalJSON decodes an order record, failing on unknown fields
+func (r *OrderRecord) UnmarshalJSON(data []byte) error {
+	var v struct {
+		OrderID  *int    `json:"order_id"`
+		OldID    *int    `json:"OrderID"`
+		Items    []int   `json:"items"`
+		Release  float64 `json:"release"`
+		Due      float64 `json:"due"`
+		Priority int     `json:"priority"`
+	}
+	d := json.NewDecoder(bytes.NewReader(data))
+	d.DisallowUnknownFields()
+	if err := d.Decode(&v); err != nil {
+		return err
+	}
+	*r = OrderRecord{Items: v.Items, Release: v.Release, Due: v.Due, Priority: v.Priority}
+	if v.OrderID == nil {
+		v.OrderID = v.OldID
+	}
+	if v.OrderID != nil {
+		r.OrderID, r.hasID = *v.OrderID, true
+	}
+	return nil
+}
+
+// ProductRecord is a product of a JSON or JSON Lines product file, at X and Y as
+// in the grid file or at Location. Dimensions, when given, are used instead of
+// those of the dimension file, as are those of the columns of a product file with
+// a header and a weight. Without Weight the weight is unknown.
+type ProductRecord struct {
+	ID       int      `json:"id"`
+	X        int      `json:"x"`
+	Y        int      `json:"y"`
+	Level    int      `json:"level,omitempty"`
+	Floor    int      `json:"floor,omitempty"`
+	Location string   `json:"location,omitempty"`
+	Length   float64  `json:"length,omitempty"`
+	Width    float64  `json:"width,omitempty"`
+	Height   float64  `json:"height,omitempty"`
+	Weight   *float64 `json:"weight,omitempty"`
+}
+
+// orderColumns, productColumns and dimensionColumns are the names the columns
+// of a header may have, in lower case with underscores for spaces
+var (
+	orderColumns = map[string][]string{
+		"order":    {"order", "order_id", "orderid", "order_no", "order_number"},
+		"item":     {"item", "item_id", "sku", "product", "product_id", "prod_id"},
+		"items":    {"items", "skus", "products"},
+		"quantity": {"quantity", "qty"},
+		"release":  {"release"},
+		"due":      {"due", "cutoff", "cut_off"},
+		"priority": {"priority"},
+	}
+	productColumns = map[string][]string{
+		"id":       {"id", "item", "item_id", "sku", "product", "product_id", "prod_id"},
+		"x":        {"x"},
+		"y":        {"y"},
+		"level":    {"level"},
+		"floor":    {"floor"},
+		"location": {"location", "loc", "slot", "bin"},
+		"length":   {"length", "len"},
+		"width":    {"width"},
+		"height":   {"height"},
+		"weight":   {"weight", "wt"},
+	}
+	dimensionColumns = map[string][]string{
+		"id":     productColumns["id"],
+		"length": {"length", "len", "l"},
+		"width":  {"width", "w"},
+		"height": {"height", "h"},
+		"weight": {"weight", "wt"},
+	}
+)
+
+// detectFormat returns the format of the file at path by its extension or, without
+// a known one, by its first character
+func detectFormat(path string, data []byte) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		return formatJSON
+	case ".jsonl", ".ndjson":
+		return formatJSONL
+	}
+	data = bytes.TrimSpace(data)
+	switch {
+	case len(data) > 0 && data[0] == '[':
+		return formatJSON
+	case len(data) > 0 && data[0] == '{':
+		return formatJSONL
+	}
+	return formatDelimited
+}
+
+// readInputFile returns the content of the file at path and its format
+func readInputFile(path string) ([]byte, string) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		log.Fatal(err)
+	}
+	return data, detectFormat(path, data)
+}
+
+// decodeRecords decodes every record of a JSON array or of JSON Lines with decode,
+// failing on unknown fields
+func decodeRecords(path string, data []byte, format string, decode func(*json.Decoder) error) {
+	d := json.NewDecoder(bytes.NewReader(data))
+	d.DisallowUnknownFields()
+	if format == formatJSON {
+		if t, err := d.Token(); err != nil || t != json.Delim('[') {
+			log.Fatalf("%v is not a JSON array.", path)
+		}
+	}
+	for i := 1; d.More(); i++ {
+		if err := decode(d); err != nil {
+			log.Fatalf("%v record %v: %v", path, i, err)
+		}
+	}
+}
+
+// readDelimited returns the records of a text file separated by tabs, commas or
+// semicolons, whichever its first line has most of, and the separator.
+// Lines starting with '#' are comments; fields are trimmed.
+func readDelimited(data []byte) ([][]string, rune) {
+	comma := ','
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || line[0] == '#' {
+			continue
+		}
+		most := strings.Count(line, ",")
+		for _, c := range []rune{'\t', ';'} {
+			if n := strings.Count(line, string(c)); n > most {
+				comma, most = c, n
+			}
+		}
+		break
+	}
+	r := csv.NewReader(bytes.NewReader(data))
+	r.Comma = comma
+	r.Comment = '#'
+	r.FieldsPerRecord = -1
+	// with tabs a leading space trim would swallow the empty fields
+	r.TrimLeadingSpace = comma != '\t'
+	records, err := r.ReadAll()
+	if err != nil {
+		log.Fatal(err)
+	}
+	for _, rec := range records {
+		for i := range rec {
+			rec[i] = strings.TrimSpace(rec[i])
+		}
+	}
+	return records, comma
+}
+
+// headerColumns returns the column of every field named in rec, or nil when rec
+// is not a header: a header has no numbers and names one field or more
+func headerColumns(rec []string, names map[string][]string) map[string]int {
+	cols := make(map[string]int)
+	for i, name := range rec {
+		if _, err := strconv.ParseFloat(name, 64); err == nil {
+			return nil
+		}
+		name = strings.NewReplacer(" ", "_", "-", "_").Replace(strings.ToLower(name))
+		for field, aliases := range names {
+			for _, a := range aliases {
+				if _, ok := cols[field]; !ok && name == a {
+					cols[field] = i
+				}
+			}
+		}
+	}
+	if len(cols) == 0 {
+		return nil
+	}
+	return cols
+}
+
+// field returns the field of rec in the column, "" when there is none
+func field(rec []string, cols map[string]int, name string) string {
+	if i, ok := cols[name]; ok && i < len(rec) {
+		return rec[i]
+	}
+	return ""
+}
+
+// fieldInt returns the field of rec in the column as int, def when empty
+func fieldInt(rec []string, cols map[string]int, name string, def int) int {
+	s := field(rec, cols, name)
+	if s == "" {
+		return def
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		log.Fatalf("Record %v: %v", rec, err)
+	}
+	return n
+}
+
+// fieldFloat returns the field of rec in the column as float64, 0 when empty
+func fieldFloat(rec []string, cols map[string]int, name string) float64 {
+	s := field(rec, cols, name)
+	if s == "" {
+		return 0
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		log.Fatalf("Record %v: %v", rec, err)
+	}
+	return f
+}
+
+// LoadOrders returns the orders of the file at path and their time windows,
+// nil when the file has none. Besides JSON and JSON Lines of OrderRecords it
+// reads text files with a header naming the order, item, items (separated by
+// spaces), quantity, release, due and priority columns, one line per item or per
+// order; the schedule lines of ParseOrderSchedule; and lines of item ids
+// separated by tabs, whose orders are numbered by line. The order ids of the file
+// are kept and the lines or records of one id merged into one order.
+func LoadOrders(path string) ([]Order, map[int]OrderInfo) {
+	data, format := readInputFile(path)
+	if format != formatDelimited {
+		return jsonOrders(path, data, format)
+	}
+	records, comma := readDelimited(data)
+	if len(records) == 0 {
+		log.Fatalf("Order file %v is empty.", path)
+	}
+	if cols := headerColumns(records[0], orderColumns); cols != nil {
+		return headerOrders(path, records[1:], cols)
+	}
+	if comma == ',' && len(records[0]) > 1 {
+		return scheduleOrders(records)
+	}
+	var orders []Order
+	for j, rec := range records {
+		var order Order
+		for _, s := range rec {
+			for _, id := range strings.Fields(s) {
+				pid, err := strconv.Atoi(id)
+				if err != nil {
+					log.Fatal(err)
+				}
+				order = append(order, Item{pid, j + 1})
+			}
+		}
+		orders = append(orders, order)
+	}
+	return orders, nil
+}
+
+func jsonOrders(path string, data []byte, format string) ([]Order, map[int]OrderInfo) {
+	var records []OrderRecord
+	timed := false
+	decodeRecords(path, data, format, func(d *json.Decoder) error {
+		var r OrderRecord
+		if err := d.Decode(&r); err != nil {
+			return err
+		}
+		timed = timed || r.Release != 0 || r.Due != 0 || r.Priority != 0
+		records = append(records, r)
+		return nil
+	})
+	orders, info := mergeOrders(records)
+	if !timed {
+		info = nil
+	}
+	return orders, info
+}
+
+// headerOrders returns the orders of the records of a file with a header
+func headerOrders(path string, records [][]string, cols map[string]int) ([]Order, map[int]OrderInfo) {
+	_, item := cols["item"]
+	_, items := cols["items"]
+	if !item && !items {
+		log.Fatalf("Order file %v has no item column.", path)
+	}
+	var rows []OrderRecord
+	for _, rec := range records {
+		r := OrderRecord{
+			OrderID:  fieldInt(rec, cols, "order", 0),
+			hasID:    field(rec, cols, "order") != "",
+			Release:  fieldFloat(rec, cols, "release"),
+			Due:      fieldFloat(rec, cols, "due"),
+			Priority: fieldInt(rec, cols, "priority", 0),
+		}
+		list := strings.Fields(field(rec, cols, "item"))
+		list = append(list, strings.Fields(strings.Replace(field(rec, cols, "items"), ";", " ", -1))...)
+		for n := fieldInt(rec, cols, "quantity", 1); n > 0; n-- {
+			for _, s := range list {
+				pid, err := strconv.Atoi(s)
+				if err != nil {
+					log.Fatalf("Record %v: %v", rec, err)
+				}
+				r.Items = append(r.Items, pid)
+			}
+		}
+		rows = append(rows, r)
+	}
+	orders, info := mergeOrders(rows)
+	_, release := cols["release"]
+	_, due := cols["due"]
+	_, priority := cols["priority"]
+	if !release && !due && !priority {
+		info = nil
+	}
+	return orders, info
+}
+
+// mergeOrders returns the orders of the records in the order their ids first
+// appear. Records of the same id are one order, timed by its first record.
+// Records without an id are numbered, in file order, with the lowest ids no
+// record of the file has.
+func mergeOrders(records []OrderRecord) ([]Order, map[int]OrderInfo) {
+	used := make(map[int]bool)
+	for _, r := range records {
+		if r.hasID {
+			used[r.OrderID] = true
+		}
+	}
+	next := 1
+	var ids []int
+	byID := make(map[int]Order)
+	info := make(map[int]OrderInfo)
+	for _, r := range records {
+		if !r.hasID {
+			for used[next] {
+				next++
+			}
+			r.OrderID = next
+			used[next] = true
+		}
+		if _, ok := info[r.OrderID]; !ok {
+			ids = append(ids, r.OrderID)
+			info[r.OrderID] = OrderInfo{r.Release, r.Due, r.Priority}
+		}
+		for _, id := range r.Items {
+			byID[r.OrderID] = append(byID[r.OrderID], Item{id, r.OrderID})
+		}
+	}
+	var orders []Order
+	for _, id := range ids {
+		if len(byID[id]) == 0 {
+			log.Fatalf("Order %v has no items.", id)
+		}
+		orders = append(orders, byID[id])
+	}
+	return orders, info
+}
+
+// loadProducts returns the products of the file at path, without their dimensions,
+// and the dimensions given in the file
+func loadProducts(path string) ([]Product, map[int][]float64) {
+	data, format := readInputFile(path)
+	var prods []Product
+	dim := make(map[int][]float64)
+	if format != formatDelimited {
+		decodeRecords(path, data, format, func(d *json.Decoder) error {
+			var r ProductRecord
+			if err := d.Decode(&r); err != nil {
+				return err
+			}
+			id := strconv.Itoa(r.ID)
+			if r.Location != "" {
+				prods = append(prods, locatedProduct([]string{id, r.Location}))
+			} else {
+				prods = append(prods, gridProduct([]string{id, strconv.Itoa(r.X), strconv.Itoa(r.Y), strconv.Itoa(r.Level), strconv.Itoa(r.Floor)}))
+			}
+			if r.Length != 0 || r.Width != 0 || r.Height != 0 || r.Weight != nil {
+				weight := math.NaN()
+				if r.Weight != nil {
+					weight = *r.Weight
+				}
+				dim[r.ID] = []float64{r.Length, r.Width, r.Height, weight}
+			}
+			return nil
+		})
+		return prods, dim
+	}
+	records, _ := readDelimited(data)
+	if len(records) == 0 {
+		log.Fatalf("Product file %v is empty.", path)
+	}
+	cols := headerColumns(records[0], productColumns)
+	if cols == nil {
+		for _, rec := range records {
+			if len(rec) > 1 && isLocation(rec[1]) {
+				prods = append(prods, locatedProduct(rec))
+			} else {
+				prods = append(prods, gridProduct(rec))
+			}
+		}
+		return prods, dim
+	}
+	if _, ok := cols["id"]; !ok {
+		log.Fatalf("Product file %v has no id column.", path)
+	}
+	_, sized := cols["weight"]
+	for _, rec := range records[1:] {
+		id := field(rec, cols, "id")
+		var prod Product
+		if loc := field(rec, cols, "location"); loc != "" {
+			prod = locatedProduct([]string{id, loc})
+		} else {
+			prod = gridProduct([]string{
+				id,
+				field(rec, cols, "x"),
+				field(rec, cols, "y"),
+				strconv.Itoa(fieldInt(rec, cols, "level", 0)),
+				strconv.Itoa(fieldInt(rec, cols, "floor", 0)),
+			})
+		}
+		if sized && field(rec, cols, "weight") != "" {
+			dim[prod.id] = []float64{
+				fieldFloat(rec, cols, "length"),
+				fieldFloat(rec, cols, "width"),
+				fieldFloat(rec, cols, "height"),
+				fieldFloat(rec, cols, "weight"),
+			}
+		}
+		prods = append(prods, prod)
+	}
+	return prods, dim
+}
@@ -0,0 +1,188 @@
+package warehouse
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// writeInput writes a file of the test's temporary directory and returns its path
+func writeInput(t *testing.T, name, data string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadOrdersLegacyCSV(t *testing.T) {
+	// tab separated item ids stay orders numbered by line whatever the file is named
+	path := writeInput(t, "orders.csv", "108\t1\t24\n3\n")
+	orders, info := LoadOrders(path)
+	want := []Order{{{108, 1}, {1, 1}, {24, 1}}, {{3, 2}}}
+	if !reflect.DeepEqual(orders, want) {
+		t.Errorf("orders = %v, want %v", orders, want)
+	}
+	if info != nil {
+		t.Errorf("info = %v, want nil", info)
+	}
+}
+
+func TestDetectFormat(t *testing.T) {
+	for _, tc := range []struct {
+		path, data, want string
+	}{
+		{"orders.json", "{}", formatJSON},
+		{"orders.JSONL", "[]", formatJSONL},
+		{"orders.ndjson", "", formatJSONL},
+		{"orders.txt", "  \n[{\"Items\": [1]}]", formatJSON},
+		{"orders", "{\"Items\": [1]}\n", formatJSONL},
+		{"orders.csv", "order,item\n1,2\n", formatDelimited},
+		{"orders.csv", "[1]", formatJSON},
+		{"orders.txt", "", formatDelimited},
+	} {
+		if got := detectFormat(tc.path, []byte(tc.data)); got != tc.want {
+			t.Errorf("detectFormat(%q, %q) = %v, want %v", tc.path, tc.data, got, tc.want)
+		}
+	}
+}
+
+func TestLoadOrders(t *testing.T) {
+	for _, tc := range []struct {
+		name, file, data string
+		want             []Order
+		info             map[int]OrderInfo
+	}{
+		{
+			name: "json ids kept",
+			file: "orders.json",
+			data: `[{"order_id": 7, "items": [1, 2]}, {"order_id": 3, "items": [2]}]`,
+			want: []Order{{{1, 7}, {2, 7}}, {{2, 3}}},
+		},
+		{
+			name: "json old field names",
+			file: "orders.json",
+			data: `[{"OrderID": 7, "Items": [1, 2], "Priority": 1}]`,
+			want: []Order{{{1, 7}, {2, 7}}},
+			info: map[int]OrderInfo{7: {Priority: 1}},
+		},
+		{
+			name: "missing ids after explicit ones",
+			file: "orders.json",
+			data: `[{"items": [1]}, {"order_id": 1, "items": [2]}, {"items": [3]}, {"order_id": 2, "items": [4]}]`,
+			want: []Order{{{1, 3}}, {{2, 1}}, {{3, 4}}, {{4, 2}}},
+		},
+		{
+			name: "explicit id 0",
+			file: "orders.json",
+			data: `[{"items": [1]}, {"order_id": 0, "items": [2]}, {"order_id": 0, "items": [3]}]`,
+			want: []Order{{{1, 1}}, {{2, 0}, {3, 0}}},
+		},
+		{
+			name: "jsonl merges one id",
+			file: "orders.jsonl",
+			data: "{\"order_id\": 5, \"items\": [1], \"due\": 60}\n{\"order_id\": 5, \"items\": [2], \"due\": 90}\n",
+			want: []Order{{{1, 5}, {2, 5}}},
+			info: map[int]OrderInfo{5: {Due: 60}},
+		},
+		{
+			name: "header aliases",
+			file: "orders.csv",
+			data: "Order No;SKU;Qty;Cut-off\n4;10;2;30\n4;11;1;30\n2;12;1;\n",
+			want: []Order{{{10, 4}, {10, 4}, {11, 4}}, {{12, 2}}},
+			info: map[int]OrderInfo{4: {Due: 30}, 2: {}},
+		},
+		{
+			name: "header id 0",
+			file: "orders.csv",
+			data: "order,item\n,1\n0,2\n",
+			want: []Order{{{1, 1}}, {{2, 0}}},
+		},
+		{
+			name: "header items and missing ids",
+			file: "orders.txt",
+			data: "order_id,products\n,1 2\n1,3;4\n",
+			want: []Order{{{1, 2}, {2, 2}}, {{3, 1}, {4, 1}}},
+		},
+		{
+			name: "tabs with an empty id",
+			file: "orders.txt",
+			data: "order_id\tproducts\n\t1 2\n1\t3;4\n",
+			want: []Order{{{1, 2}, {2, 2}}, {{3, 1}, {4, 1}}},
+		},
+		{
+			name: "header without order column",
+			file: "orders.csv",
+			data: "item,quantity\n5,1\n6,2\n",
+			want: []Order{{{5, 1}}, {{6, 2}, {6, 2}}},
+		},
+		{
+			name: "legacy tab orders",
+			file: "orders.txt",
+			data: "1\t2\n3 4\t5\n",
+			want: []Order{{{1, 1}, {2, 1}}, {{3, 2}, {4, 2}, {5, 2}}},
+		},
+		{
+			name: "schedule",
+			file: "orders.csv",
+			data: "9, 0, 120, 1, 1, 2\n9, 30, 60, 2, 3\n",
+			want: []Order{{{1, 9}, {2, 9}, {3, 9}}},
+			info: map[int]OrderInfo{9: {0, 120, 1}},
+		},
+	} {
+		orders, info := LoadOrders(writeInput(t, tc.file, tc.data))
+		if !reflect.DeepEqual(orders, tc.want) {
+			t.Errorf("%v: orders = %v, want %v", tc.name, orders, tc.want)
+		}
+		if !reflect.DeepEqual(info, tc.info) {
+			t.Errorf("%v: info = %v, want %v", tc.name, info, tc.info)
+		}
+	}
+}
+
+func TestParesDimensionInfo(t *testing.T) {
+	for _, tc := range []struct {
+		name, data string
+		want       map[int][]float64
+	}{
+		{"no header", "1,2,3,4,5\n", map[int][]float64{1: {2, 3, 4, 5}}},
+		{"unknown header", "a,b,c,d,e\n1,2,3,4,5\n", map[int][]float64{1: {2, 3, 4, 5}}},
+		{"full header", "wt;h;w;l;sku\n5;4;3;2;1\n", map[int][]float64{1: {2, 3, 4, 5}}},
+		{"partial header", "sku\tweight\tl\n1\t5\n2\t\t3\n", map[int][]float64{1: {0, 0, 0, 5}, 2: {3, 0, 0, math.NaN()}}},
+	} {
+		// an unknown weight is NaN, which DeepEqual never matches
+		if got := ParesDimensionInfo(writeInput(t, "dim.csv", tc.data)); fmt.Sprint(got) != fmt.Sprint(tc.want) {
+			t.Errorf("%v: %v, want %v", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestOrderRecordUnknownField(t *testing.T) {
+	var r OrderRecord
+	if err := json.Unmarshal([]byte(`{"order": 1, "items": [2]}`), &r); err == nil {
+		t.Errorf("decoded %+v, want an error for the unknown field", r)
+	}
+}
+
+func TestUnknownWeights(t *testing.T) {
+	testProducts(t)
+	for _, tc := range []struct {
+		name, file, data string
+		dim              map[int][]float64
+	}{
+		{"json without weight", "products.json", `[{"id": 1, "x": 0, "y": 0, "height": 2}, {"id": 2, "x": 1, "y": 0, "weight": 0}]`, nil},
+		{"empty weight cell", "products.csv", "0, 0, 0\n2, 1, 0\n", ParesDimensionInfo(writeInput(t, "dim.csv", "sku,weight\n0,\n2,0\n"))},
+	} {
+		m := ParseProductInfo(writeInput(t, tc.file, tc.data), tc.dim)
+		for id, want := range map[int]bool{0: false, 1: false, 2: true} {
+			if p, ok := m[id]; ok && p.wAvail != want {
+				t.Errorf("%v: product %v has a known weight: %v, want %v", tc.name, id, p.wAvail, want)
+			}
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package warehouse
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+)
+
+// RouteProgress is a route being walked: the picker stands at Pos, the items of
+// Done are picked and Remaining is the rest of the sequence walked to End
+type RouteProgress struct {
+	Pos             Point
+	Done, Remaining Order
+	End             Point
+}
+
+// DecodeRouteProgress returns the route in progress encoded in data, whose Pos and End
+// may be location names of the layout
+func DecodeRouteProgress(data []byte, l Layout) (RouteProgress, error) {
+	var r struct {
+		Pos             Location
+		Done, Remaining Order
+		End             Location
+	}
+	var p RouteProgress
+	if err := json.Unmarshal(data, &r); err != nil {
+		return p, err
+	}
+	p.Done, p.Remaining = r.Done, r.Remaining
+	var err error
+	if p.Pos, err = l.Locate(r.Pos); err != nil {
+		return p, err
+	}
+	p.End, err = l.Locate(r.End)
+	return p, err
+}
+
+// InsertItems returns the remaining Order with every new item inserted where it
+// adds the least to the route from pos to end (cheapest insertion).
+// Positions that break fewer precedence rules are preferred.
+func InsertItems(remaining, items Order, pos, end Point, m map[int]Product, pathInfo map[Point]map[Point]float64) Order {
+	order := append(Order{}, remaining...)
+	for _, item := range items {
+		var best Order
+		min := math.Inf(1)
+		minViolations := -1
+		for i := 0; i <= len(order); i++ {
+			candidate := make(Order, 0, len(order)+1)
+			candidate = append(append(append(candidate, order[:i]...), item), order[i:]...)
+			violations := len(PrecedenceViolations(candidate, m))
+			length := RouteCost(candidate, pos, end, m, pathInfo)
+			if minViolations < 0 || violations < minViolations || (violations == minViolations && length < min) {
+				minViolations = violations
+				min = length
+				best = candidate
+			}
+		}
+		order = best
+	}
+	return order
+}
+
+// UpdateRoute returns the progress with the new items added to the remaining tour.
+// The items are placed by cheapest insertion; when opt is not nil the remaining
+// tour from the current position is also optimized again by opt and the shorter
+// of both is kept. The picks already done are left untouched.
+// It fails when the position or the end is not in the aisles or an item is unknown.
+func UpdateRoute(p RouteProgress, items Order, m map[int]Product, pathInfo map[Point]map[Point]float64, opt Optimizer) (RouteProgress, error) {
+	for _, q := range []Point{p.Pos, p.End} {
+		if _, ok := pathInfo[q]; !ok {
+			return p, fmt.Errorf("position %v is not in the aisles", q)
+		}
+	}
+	for _, item := range append(append(Order{}, p.Remaining...), items...) {
+		if _, ok := m[item.ProdID]; !ok {
+			return p, fmt.Errorf("item id %v not found", item.ProdID)
+		}
+	}
+	remaining := InsertItems(p.Remaining, items, p.Pos, p.End, m, pathInfo)
+	if opt != nil && len(remaining) > 1 {
+		reopt := opt(remaining, p.Pos, p.End, m, pathInfo)
+		if len(PrecedenceViolations(reopt, m)) <= len(PrecedenceViolations(remaining, m)) &&
+			RouteCost(reopt, p.Pos, p.End, m, pathInfo) < RouteCost(remaining, p.Pos, p.End, m, pathInfo) {
+			remaining = reopt
+		}
+	}
+	return RouteProgress{Pos: p.Pos, Done: append(Order{}, p.Done...), Remaining: remaining, End: p.End}, nil
+}
@@ -0,0 +1,230 @@
+package warehouse
+
+import (
+	"fmt"
+	"log"
+)
+
+// Layout defines the grid of the warehouse and its physical dimensions in meters.
+// Columns with even X are aisles of width PathWidthX and odd ones are shelves
+// of ShelfLength; rows with even Y are cross aisles of width PathWidthY and
+// odd ones are bays of ShelfWidth. Columns and Rows override the size of
+// single columns and rows, e.g. a wider aisle or a zone with longer bays.
+// LevelCost is the cost in meters of reaching one shelf level up, charged at
+// every pick by the level of its product.
+// Every one of the Floors has the same grid, and Connectors link them.
+// Zones are listed in the order the conveyor passes them.
+// Naming is how the locations are named for the workers.
+type Layout struct {
+	MaxX, MaxY  int
+	Floors      int
+	Connectors  []Connector
+	Zones       []Zone
+	ShelfLength float64
+	ShelfWidth  float64
+	PathWidthX  float64
+	PathWidthY  float64
+	LevelCost   float64
+	Columns     map[int]float64
+	Rows        map[int]float64
+	Naming      Naming
+	colPos      []float64
+	rowPos      []float64
+}
+
+// DefaultLayout is the layout used when no layout file is given
+var DefaultLayout = Layout{MaxX: 38, MaxY: 22, Floors: 1, ShelfLength: 1.0, ShelfWidth: 1.0, PathWidthX: 1.0, PathWidthY: 1.0, Naming: DefaultNaming}
+
+// layout is the Layout used to measure paths and to build the path info
+var layout = DefaultLayout.measured()
+
+// SetLayout sets the Layout used to measure paths and to build the path info
+func SetLayout(l Layout) {
+	layout = l.measured()
+}
+
+// LoadLayout returns the layout from a config file of "size, maxX, maxY",
+// "shelf_length|shelf_width|path_width_x|path_width_y|level_cost, meters",
+// "column, x, meters", "row, y, meters", "floors, n" and
+// "connector, name, x, y, cost, capacity, floor, floor[, floor...]" and
+// "zone, name, x1, y1, x2, y2, inductX, inductY[, floor]" lines, and for the
+// location names "aisle_prefix|bay_prefix|cross_prefix|level_prefix|floor_prefix, letters",
+// "sides, left, right", "separator, s" and "digits, n". The points of connectors
+// and zones may also be given as location names, e.g. "zone, name, A01-X01, A04-X05, A01-X01".
+func LoadLayout(path string) Layout {
+	l := DefaultLayout
+	records, ok := readConfig(path)
+	if !ok {
+		return l
+	}
+	l.Columns = make(map[int]float64)
+	l.Rows = make(map[int]float64)
+	var placed [][]string
+	for _, rec := range records {
+		switch rec[0] {
+		case "size":
+			l.MaxX, l.MaxY = configInt(rec, 1), configInt(rec, 2)
+		case "shelf_length":
+			l.ShelfLength = configFloat(rec, 1)
+		case "shelf_width":
+			l.ShelfWidth = configFloat(rec, 1)
+		case "path_width_x":
+			l.PathWidthX = configFloat(rec, 1)
+		case "path_width_y":
+			l.PathWidthY = configFloat(rec, 1)
+		case "level_cost":
+			l.LevelCost = configFloat(rec, 1)
+		case "column":
+			l.Columns[configInt(rec, 1)] = configFloat(rec, 2)
+		case "row":
+			l.Rows[configInt(rec, 1)] = configFloat(rec, 2)
+		case "floors":
+			l.Floors = configInt(rec, 1)
+		case "connector", "zone":
+			// read once the names of the locations are known
+			placed = append(placed, rec)
+		case "aisle_prefix":
+			l.Naming.Aisle = configString(rec, 1)
+		case "bay_prefix":
+			l.Naming.Bay = configString(rec, 1)
+		case "cross_prefix":
+			l.Naming.Cross = configString(rec, 1)
+		case "level_prefix":
+			l.Naming.Level = configString(rec, 1)
+		case "floor_prefix":
+			l.Naming.Floor = configString(rec, 1)
+		case "sides":
+			l.Naming.Left, l.Naming.Right = configString(rec, 1), configString(rec, 2)
+		case "separator":
+			l.Naming.Separator = configString(rec, 1)
+		case "digits":
+			l.Naming.Digits = configInt(rec, 1)
+		default:
+			log.Fatalf("Unknown layout entry %q.", rec[0])
+		}
+	}
+	l.Naming.check()
+	for _, rec := range placed {
+		if rec[0] == "connector" {
+			l.Connectors = append(l.Connectors, l.configConnector(rec))
+		} else {
+			l.Zones = append(l.Zones, l.configZone(rec))
+		}
+	}
+	return l
+}
+
+// configConnector returns the connector of a config record
+func (l Layout) configConnector(rec []string) Connector {
+	p, n, err := l.configPoint(rec, 2)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if len(rec) < 6+n {
+		log.Fatalf("Connector entry %v is too short.", rec)
+	}
+	c := Connector{
+		Name:     rec[1],
+		X:        p.X,
+		Y:        p.Y,
+		Cost:     configFloat(rec, 2+n),
+		Capacity: configInt(rec, 3+n),
+	}
+	for i := 4 + n; i < len(rec); i++ {
+		c.Floors = append(c.Floors, configInt(rec, i))
+	}
+	if c.X*c.Y%2 == 1 || len(c.Floors) < 2 {
+		log.Fatalf("Connector %v must stand in an aisle and serve 2 floors or more.", c.Name)
+	}
+	return c
+}
+
+// configZone returns the zone of a config record. Its floor is that of the induct
+// point when named, unless given after it.
+func (l Layout) configZone(rec []string) Zone {
+	// the corners and the induct point
+	var points [3]Point
+	i := 2
+	for j := range points {
+		p, n, err := l.configPoint(rec, i)
+		if err != nil {
+			log.Fatal(err)
+		}
+		points[j] = p
+		i += n
+	}
+	z := Zone{
+		Name: rec[1],
+		X1:   points[0].X,
+		Y1:   points[0].Y,
+		X2:   points[1].X,
+		Y2:   points[1].Y,
+		F:    points[2].F,
+	}
+	if len(rec) > i {
+		z.F = configInt(rec, i)
+	}
+	z.Induct = Point{points[2].X, points[2].Y, z.F}
+	if z.Induct.X*z.Induct.Y%2 == 1 {
+		log.Fatalf("Induct point of zone %v is on a shelf.", z.Name)
+	}
+	return z
+}
+
+// ColumnWidth returns the width in meters of the column at x
+func (l Layout) ColumnWidth(x int) float64 {
+	if w, ok := l.Columns[x]; ok {
+		return w
+	}
+	if x%2 == 0 {
+		return l.PathWidthX
+	}
+	return l.ShelfLength
+}
+
+// RowWidth returns the width in meters of the row at y
+func (l Layout) RowWidth(y int) float64 {
+	if w, ok := l.Rows[y]; ok {
+		return w
+	}
+	if y%2 == 0 {
+		return l.PathWidthY
+	}
+	return l.ShelfWidth
+}
+
+// measured returns the layout with the center of every column and row in meters
+func (l Layout) measured() Layout {
+	l.colPos = make([]float64, l.MaxX+1)
+	l.rowPos = make([]float64, l.MaxY+1)
+	var x, y float64
+	for i := range l.colPos {
+		w := l.ColumnWidth(i)
+		l.colPos[i] = x + w/2
+		x += w
+	}
+	for j := range l.rowPos {
+		w := l.RowWidth(j)
+		l.rowPos[j] = y + w/2
+		y += w
+	}
+	return l
+}
+
+// Contains reports whether the point is on the grid of the layout
+func (l Layout) Contains(p Point) bool {
+	return p.X >= 0 && p.X <= l.MaxX && p.Y >= 0 && p.Y <= l.MaxY && p.F >= 0 && p.F < l.Floors
+}
+
+// Meters returns the position of the center of the point in meters
+func (l Layout) Meters(p Point) (float64, float64, error) {
+	if !l.Contains(p) {
+		return 0, 0, fmt.Errorf("point %v is out of the warehouse", p)
+	}
+	return l.colPos[p.X], l.rowPos[p.Y], nil
+}
+
+// CurrentLayout returns the Layout in use
+func CurrentLayout() Layout {
+	return layout
+}
@@ -48,19 +48,19 @@ func ByWeightReverse(o1, o2 *Order, m map[int]Product) bool {
 	return OrderWeight(*o1, m) > OrderWeight(*o2, m)
 }
 
-// MergeOrders returns the reconbined order IDs that has total weight not larger than max
-func MergeOrders(orders []Order, m map[int]Product, max float64) []Order {
+// MergeOrders returns the reconbined order IDs that fit in the cart capacity c
+func MergeOrders(orders []Order, m map[int]Product, c Capacity) []Order {
 	reOrders := make([]Order, 1)
-	ordersWeight := []float64{0.0}
+	loads := []Capacity{Capacity{}}
 	By(ByWeightReverse).Sort(orders, m)
 	var fit bool
 	for _, o := range orders {
 		fit = false
+		ol := OrderLoad(o, m)
 		for j := range reOrders {
-			ow := OrderWeight(o, m)
-			if (ordersWeight[j]+ow <= max && len(reOrders[j]) + len(o) <= maxItem ) || len(reOrders[j]) == 0 {
+			if c.Fits(loads[j].Add(ol)) || len(reOrders[j]) == 0 {
 				reOrders[j] = append(reOrders[j], o...)
-				ordersWeight[j] += ow
+				loads[j] = loads[j].Add(ol)
 				fit = true
 				break
 			}
@@ -69,7 +69,7 @@ func MergeOrders(orders []Order, m map[int]Product, max float64) []Order {
 			var newOrder Order
 			newOrder = append(newOrder, o...)
 			reOrders = append(reOrders, newOrder)
-			ordersWeight = append(ordersWeight, OrderWeight(o, m))
+			loads = append(loads, ol)
 		}
 	}
 	return reOrders
@@ -0,0 +1,291 @@
+package warehouse
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Naming is how the locations of the grid are named for the workers, e.g. A07-B04R-L2:
+// aisle 7, bay 4, the shelf on the right (the higher X) of the aisle, level 2.
+// Every shelf has one name, after the aisle on its left, even when it is picked
+// from the aisle on its right. The ground level 0 is left out, like in A07-B04R.
+// A point of a cross-aisle is named like A07-X03 and gets a side when it lies
+// between two aisles; with several floors names start with the floor, like F1-A07-B04.
+// Numbers of aisles, bays and cross-aisles are padded to Digits digits.
+type Naming struct {
+	Aisle, Bay, Cross, Level, Floor string
+	Left, Right                     string
+	Separator                       string
+	Digits                          int
+}
+
+// DefaultNaming is the Naming used when the layout file does not set one
+var DefaultNaming = Naming{"A", "B", "X", "L", "F", "L", "R", "-", 2}
+
+// aisleNumber returns the aisle at x, counted from 1
+func aisleNumber(x int) int {
+	return x/2 + 1
+}
+
+// bayNumber returns the bay at y, counted from 1
+func bayNumber(y int) int {
+	return (y + 1) / 2
+}
+
+// crossNumber returns the cross-aisle at y, counted from 1
+func crossNumber(y int) int {
+	return y/2 + 1
+}
+
+// check stops when the names could not be read back
+func (n Naming) check() {
+	prefixes := []string{n.Aisle, n.Bay, n.Cross, n.Level, n.Floor}
+	for i, p := range prefixes {
+		if p == "" || strings.IndexFunc(p, unicode.IsDigit) >= 0 {
+			log.Fatalf("Location prefix %q must be letters.", p)
+		}
+		for _, q := range prefixes[:i] {
+			if strings.EqualFold(p, q) {
+				log.Fatalf("Location prefix %q is used twice.", p)
+			}
+		}
+	}
+	if n.Left == "" || n.Right == "" || strings.EqualFold(n.Left, n.Right) ||
+		strings.IndexFunc(n.Left+n.Right, unicode.IsDigit) >= 0 {
+		log.Fatalf("Side letters %q and %q must be distinct letters.", n.Left, n.Right)
+	}
+	if n.Separator == "" || n.Digits < 1 {
+		log.Fatal("Locations need a separator and 1 digit or more.")
+	}
+}
+
+func (n Naming) number(prefix string, i int) string {
+	return fmt.Sprintf("%v%0*d", prefix, n.Digits, i)
+}
+
+// name returns the name of the place at p seen from the aisle at x, with the side of p
+func (l Layout) name(x int, p Point) string {
+	n := l.Naming
+	var parts []string
+	if l.Floors > 1 {
+		parts = append(parts, fmt.Sprintf("%v%d", n.Floor, p.F))
+	}
+	parts = append(parts, n.number(n.Aisle, aisleNumber(x)))
+	s := n.number(n.Cross, crossNumber(p.Y))
+	if p.Y%2 == 1 {
+		s = n.number(n.Bay, bayNumber(p.Y))
+	}
+	switch {
+	case p.X < x:
+		s += n.Left
+	case p.X > x:
+		s += n.Right
+	}
+	return strings.Join(append(parts, s), n.Separator)
+}
+
+// LocationName returns the name of the point, e.g. A07-B04 in an aisle or A07-B04R
+// for a shelf, which is named after the aisle on its left
+func (l Layout) LocationName(p Point) string {
+	x := p.X
+	if x%2 == 1 {
+		x--
+	}
+	return l.name(x, p)
+}
+
+// SlotName returns the name of the shelf at p and the level, e.g. A07-B04R-L2,
+// or A07-B04R on the ground
+func (l Layout) SlotName(p Point, level int) string {
+	s := l.LocationName(p)
+	if level != 0 {
+		s += l.Naming.Separator + fmt.Sprintf("%v%d", l.Naming.Level, level)
+	}
+	return s
+}
+
+// LocationName returns the name of the point in the layout in use
+func LocationName(p Point) string {
+	return layout.LocationName(p)
+}
+
+// ProductLocation returns the name of the shelf and level of the product in the layout in use
+func ProductLocation(prod Product) string {
+	return layout.SlotName(prod.Pos, prod.Level)
+}
+
+// ParseLocation returns the point and the level named by s in the layout in use
+func ParseLocation(s string) (Point, int, error) {
+	return layout.ParseLocation(s)
+}
+
+// ParseLocation returns the point and the level named by s, as written by
+// LocationName or SlotName; case does not matter and the level is 0 when
+// not given. A shelf can also be named from the aisle on its right.
+func (l Layout) ParseLocation(s string) (Point, int, error) {
+	n := l.Naming
+	var p Point
+	var level int
+	var aisle, along bool
+	side := 0
+	for _, part := range strings.Split(strings.ToUpper(strings.TrimSpace(s)), strings.ToUpper(n.Separator)) {
+		prefix, num, suffix := splitPart(part)
+		i, err := strconv.Atoi(num)
+		if err != nil {
+			return p, 0, fmt.Errorf("location %q: cannot read %q", s, part)
+		}
+		if suffix != "" {
+			switch {
+			case suffix == strings.ToUpper(n.Left):
+				side = -1
+			case suffix == strings.ToUpper(n.Right):
+				side = 1
+			default:
+				return p, 0, fmt.Errorf("location %q: unknown side %q", s, suffix)
+			}
+		}
+		switch prefix {
+		case strings.ToUpper(n.Floor):
+			p.F = i
+		case strings.ToUpper(n.Aisle):
+			p.X, aisle = 2*(i-1), true
+		case strings.ToUpper(n.Bay):
+			p.Y, along = 2*i-1, true
+		case strings.ToUpper(n.Cross):
+			p.Y, along = 2*(i-1), true
+		case strings.ToUpper(n.Level):
+			level = i
+		default:
+			return p, 0, fmt.Errorf("location %q: unknown part %q", s, part)
+		}
+	}
+	if !aisle || !along {
+		return p, 0, fmt.Errorf("location %q needs an aisle and a bay or a cross-aisle", s)
+	}
+	p.X += side
+	if !l.Contains(p) {
+		return p, 0, fmt.Errorf("location %q is out of the warehouse", s)
+	}
+	return p, level, nil
+}
+
+// splitPart splits a part of a name into its prefix, its number and its side
+func splitPart(part string) (string, string, string) {
+	i := strings.IndexFunc(part, unicode.IsDigit)
+	if i < 0 {
+		return part, "", ""
+	}
+	j := i + strings.IndexFunc(part[i:], func(r rune) bool { return !unicode.IsDigit(r) })
+	if j < i {
+		j = len(part)
+	}
+	return part[:i], part[i:j], part[j:]
+}
+
+// isLocation tells whether s is a location name rather than a number
+func isLocation(s string) bool {
+	s = strings.TrimSpace(s)
+	return s != "" && unicode.IsLetter(rune(s[0]))
+}
+
+// configPoint returns the point at the i-th field of a config record, either a
+// location name or x and y, and the number of fields it takes
+func configPoint(rec []string, i int) (Point, int, error) {
+	return layout.configPoint(rec, i)
+}
+
+// configPoint returns the point at the i-th field of a config record in the layout
+func (l Layout) configPoint(rec []string, i int) (Point, int, error) {
+	if i < len(rec) && isLocation(rec[i]) {
+		p, _, err := l.ParseLocation(rec[i])
+		return p, 1, err
+	}
+	if i+1 >= len(rec) {
+		return Point{}, 0, fmt.Errorf("config entry %v is missing a point at field #%v", rec, i)
+	}
+	x, err := strconv.Atoi(rec[i])
+	if err != nil {
+		return Point{}, 0, fmt.Errorf("config entry %v: %v", rec, err)
+	}
+	y, err := strconv.Atoi(rec[i+1])
+	if err != nil {
+		return Point{}, 0, fmt.Errorf("config entry %v: %v", rec, err)
+	}
+	p := Point{X: x, Y: y}
+	if !l.Contains(p) {
+		return p, 2, fmt.Errorf("config entry %v: point %v is out of the warehouse", rec, p)
+	}
+	return p, 2, nil
+}
+
+// ReadLocation returns a point from stdin, either "x y" or a location name.
+// With several floors the numbers are "x y floor".
+func ReadLocation() Point {
+	s := ReadString()
+	if !isLocation(s) {
+		x, err := strconv.Atoi(s)
+		if err != nil {
+			log.Fatal(err)
+		}
+		y, err := strconv.Atoi(ReadString())
+		if err != nil {
+			log.Fatal(err)
+		}
+		p := Point{X: x, Y: y}
+		if layout.Floors > 1 {
+			if p.F, err = strconv.Atoi(ReadString()); err != nil {
+				log.Fatal(err)
+			}
+		}
+		if !layout.Contains(p) {
+			log.Fatalf("Point %v is out of the warehouse.", p)
+		}
+		return p
+	}
+	p, _, err := ParseLocation(s)
+	if err != nil {
+		log.Fatal(err)
+	}
+	return p
+}
+
+// Location is a point read from JSON either as an object of X, Y and F or as
+// a location name. Reading it does not need the layout: the name is translated
+// by Locate with the layout of the reader.
+type Location struct {
+	Name  string
+	Point Point
+}
+
+// UnmarshalJSON reads a location name or an object of X, Y and F
+func (loc *Location) UnmarshalJSON(data []byte) error {
+	*loc = Location{}
+	if err := json.Unmarshal(data, &loc.Name); err == nil {
+		return nil
+	}
+	return json.Unmarshal(data, &loc.Point)
+}
+
+// MarshalJSON writes the name of the location, or its point when it has none
+func (loc Location) MarshalJSON() ([]byte, error) {
+	if loc.Name != "" {
+		return json.Marshal(loc.Name)
+	}
+	return json.Marshal(loc.Point)
+}
+
+// Locate returns the point of the location in the layout
+func (l Layout) Locate(loc Location) (Point, error) {
+	if loc.Name == "" {
+		if !l.Contains(loc.Point) {
+			return loc.Point, fmt.Errorf("point %v is out of the warehouse", loc.Point)
+		}
+		return loc.Point, nil
+	}
+	p, _, err := l.ParseLocation(loc.Name)
+	return p, err
+}
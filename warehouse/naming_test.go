@@ -0,0 +1,85 @@
+package warehouse
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestLocationNames(t *testing.T) {
+	l := DefaultLayout.measured()
+	for _, tc := range []struct {
+		p     Point
+		level int
+		name  string
+	}{
+		{Point{4, 7, 0}, 0, "A03-B04"},
+		{Point{5, 7, 0}, 2, "A03-B04R-L2"},
+		{Point{5, 7, 0}, 0, "A03-B04R"},
+		{Point{0, 0, 0}, 0, "A01-X01"},
+		{Point{3, 0, 0}, 0, "A02-X01R"},
+	} {
+		if got := l.SlotName(tc.p, tc.level); got != tc.name {
+			t.Errorf("%v level %v: name %q, want %q", tc.p, tc.level, got, tc.name)
+		}
+		p, level, err := l.ParseLocation(tc.name)
+		if err != nil || p != tc.p || level != tc.level {
+			t.Errorf("%q: %v level %v (%v), want %v level %v", tc.name, p, level, err, tc.p, tc.level)
+		}
+	}
+	// a shelf named from the aisle on its right is the same shelf
+	if p, _, err := l.ParseLocation("a04-b04l"); err != nil || p != (Point{5, 7, 0}) {
+		t.Errorf("A04-B04L: %v (%v), want (5, 7)", p, err)
+	}
+	for _, bad := range []string{"A03", "Q03-B04", "A03-B04X", "A40-B01"} {
+		if _, _, err := l.ParseLocation(bad); err == nil {
+			t.Errorf("%q: no error", bad)
+		}
+	}
+}
+
+func TestLocationJSONUsesTheReadersLayout(t *testing.T) {
+	l := DefaultLayout
+	l.Naming.Aisle, l.Naming.Separator = "G", "."
+	l = l.measured()
+	var req struct{ Start, End Location }
+	// the global layout names aisles A, so reading must not translate the names
+	if err := json.Unmarshal([]byte(`{"Start": "G03.B04R", "End": {"X": 2, "Y": 0}}`), &req); err != nil {
+		t.Fatal(err)
+	}
+	start, err := l.Locate(req.Start)
+	if err != nil || start != (Point{5, 7, 0}) {
+		t.Errorf("start %v (%v), want (5, 7)", start, err)
+	}
+	end, err := l.Locate(req.End)
+	if err != nil || end != (Point{2, 0, 0}) {
+		t.Errorf("end %v (%v), want (2, 0)", end, err)
+	}
+	if _, err := DefaultLayout.measured().Locate(req.Start); err == nil {
+		t.Errorf("G03.B04R read with the default naming")
+	}
+}
+
+func TestLoadLayoutLocations(t *testing.T) {
+	// the naming comes after the points it names
+	l := LoadLayout(writeInput(t, "layout.csv", `floors, 2
+connector, lift, G02.X02, 3, 1, 0, 1
+connector, stairs, 6, 2, 5, 0, 0, 1
+zone, named, G01.X01, G03.X05, G02.X01, 1
+zone, numbered, 0, 0, 4, 8, 2, 0
+aisle_prefix, G
+separator, .
+`))
+	if want := []Connector{
+		{Name: "lift", X: 2, Y: 2, Floors: []int{0, 1}, Cost: 3, Capacity: 1},
+		{Name: "stairs", X: 6, Y: 2, Floors: []int{0, 1}, Cost: 5},
+	}; !reflect.DeepEqual(l.Connectors, want) {
+		t.Errorf("connectors %+v, want %+v", l.Connectors, want)
+	}
+	if want := []Zone{
+		{Name: "named", X1: 0, Y1: 0, X2: 4, Y2: 8, F: 1, Induct: Point{2, 0, 1}},
+		{Name: "numbered", X1: 0, Y1: 0, X2: 4, Y2: 8, Induct: Point{2, 0, 0}},
+	}; !reflect.DeepEqual(l.Zones, want) {
+		t.Errorf("zones %+v, want %+v", l.Zones, want)
+	}
+}
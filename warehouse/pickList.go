@@ -0,0 +1,197 @@
+package warehouse
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/csv"
+	"fmt"
+	"html"
+	"log"
+	"math"
+	"os"
+	"strconv"
+)
+
+// PickRow is a line of a printable pick list. Aisle and Bay count from 1;
+// Side is "L" when the shelf is on the lower X side of the aisle, "R" otherwise.
+// Location is the name of the shelf and level, e.g. A07-B04R-L2, whichever aisle it is picked from.
+// Distance is the distance walked in the trip up to the pick.
+type PickRow struct {
+	Trip     int
+	Seq      int
+	SKU      int
+	OrderID  int
+	Location string
+	Floor    int
+	Aisle    int
+	Bay      int
+	Side     string
+	Level    int
+	Weight   *float64
+	Distance float64
+}
+
+// pickListHeader are the column names of the pick list
+var pickListHeader = []string{"Trip", "Seq", "SKU", "Order", "Location", "Floor", "Aisle", "Bay", "Side", "Level", "Weight", "Distance"}
+
+// PickList returns the pick list of the plan, trips and picks counted from 1
+func PickList(plan Plan) []PickRow {
+	var rows []PickRow
+	for t, trip := range plan.Trips {
+		var distance float64
+		seq := 0
+		for _, step := range trip.Steps {
+			if step.Type == StepWalk {
+				distance += step.Length
+				continue
+			}
+			seq++
+			row := PickRow{Trip: t + 1, Seq: seq, SKU: step.SKU, OrderID: step.OrderID, Level: step.Level, Weight: step.Weight, Distance: distance}
+			if step.Location != nil {
+				loc := *step.Location
+				access := loc
+				if step.Access != nil {
+					access = *step.Access
+				}
+				row.Location = layout.SlotName(loc.Point(), step.Level)
+				row.Floor = loc.Floor
+				row.Aisle = aisleNumber(access.X)
+				row.Bay = bayNumber(loc.Y)
+				row.Side = "R"
+				if loc.X < access.X {
+					row.Side = "L"
+				}
+			}
+			rows = append(rows, row)
+		}
+	}
+	return rows
+}
+
+// strings returns the cells of the row
+func (r PickRow) strings() []string {
+	weight := ""
+	if r.Weight != nil {
+		weight = strconv.FormatFloat(*r.Weight, 'f', -1, 64)
+	}
+	return []string{
+		strconv.Itoa(r.Trip),
+		strconv.Itoa(r.Seq),
+		strconv.Itoa(r.SKU),
+		strconv.Itoa(r.OrderID),
+		r.Location,
+		strconv.Itoa(r.Floor),
+		strconv.Itoa(r.Aisle),
+		strconv.Itoa(r.Bay),
+		r.Side,
+		strconv.Itoa(r.Level),
+		weight,
+		strconv.FormatFloat(r.Distance, 'f', 1, 64),
+	}
+}
+
+// WritePickListCSV writes the pick list to the file at path as CSV with a header line
+func WritePickListCSV(path string, rows []PickRow) {
+	file, err := os.Create(path)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer file.Close()
+	w := csv.NewWriter(file)
+	w.Write(pickListHeader)
+	for _, r := range rows {
+		w.Write(r.strings())
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// xlsxFiles are the parts of a workbook with a single sheet but the sheet itself
+var xlsxFiles = []struct{ name, body string }{
+	{"[Content_Types].xml", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">
+<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>
+<Default Extension="xml" ContentType="application/xml"/>
+<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>
+<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>
+</Types>`},
+	{"_rels/.rels", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>
+</Relationships>`},
+	{"xl/workbook.xml", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">
+<sheets><sheet name="Pick list" sheetId="1" r:id="rId1"/></sheets>
+</workbook>`},
+	{"xl/_rels/workbook.xml.rels", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>
+</Relationships>`},
+}
+
+// xlsxColumn returns the letters of the i-th column counted from 0
+func xlsxColumn(i int) string {
+	s := ""
+	for i++; i > 0; i = (i - 1) / 26 {
+		s = string(rune('A'+(i-1)%26)) + s
+	}
+	return s
+}
+
+// xlsxRow returns a row of the sheet. Cells that read as finite numbers are written
+// as numbers so the spreadsheet can sum them; the others, NaN and infinities
+// included, as inline strings.
+func xlsxRow(n int, cells []string) string {
+	s := fmt.Sprintf(`<row r="%d">`, n)
+	for i, c := range cells {
+		ref := fmt.Sprintf("%v%d", xlsxColumn(i), n)
+		if c == "" {
+			continue
+		}
+		if f, err := strconv.ParseFloat(c, 64); err == nil && !math.IsNaN(f) && !math.IsInf(f, 0) {
+			s += fmt.Sprintf(`<c r="%v"><v>%v</v></c>`, ref, strconv.FormatFloat(f, 'g', -1, 64))
+		} else {
+			s += fmt.Sprintf(`<c r="%v" t="inlineStr"><is><t>%v</t></is></c>`, ref, html.EscapeString(c))
+		}
+	}
+	return s + "</row>"
+}
+
+// WritePickListXLSX writes the pick list to the file at path as an Excel workbook
+func WritePickListXLSX(path string, rows []PickRow) {
+	file, err := os.Create(path)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer file.Close()
+	z := zip.NewWriter(file)
+	for _, f := range xlsxFiles {
+		w, err := z.Create(f.name)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if _, err := w.Write([]byte(f.body)); err != nil {
+			log.Fatal(err)
+		}
+	}
+	w, err := z.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		log.Fatal(err)
+	}
+	sheet := bufio.NewWriter(w)
+	sheet.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`)
+	sheet.WriteString(xlsxRow(1, pickListHeader))
+	for i, r := range rows {
+		sheet.WriteString(xlsxRow(i+2, r.strings()))
+	}
+	sheet.WriteString("</sheetData></worksheet>")
+	if err := sheet.Flush(); err != nil {
+		log.Fatal(err)
+	}
+	if err := z.Close(); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,23 @@
+package warehouse
+
+import "testing"
+
+func TestXLSXRowNumbers(t *testing.T) {
+	for _, tc := range []struct {
+		cell, want string
+	}{
+		{"12", `<c r="A1"><v>12</v></c>`},
+		{"2.5", `<c r="A1"><v>2.5</v></c>`},
+		{"0x1p-2", `<c r="A1"><v>0.25</v></c>`},
+		{"NaN", `<c r="A1" t="inlineStr"><is><t>NaN</t></is></c>`},
+		{"+Inf", `<c r="A1" t="inlineStr"><is><t>+Inf</t></is></c>`},
+		{"-infinity", `<c r="A1" t="inlineStr"><is><t>-infinity</t></is></c>`},
+		{"1e400", `<c r="A1" t="inlineStr"><is><t>1e400</t></is></c>`},
+		{"A01-B02", `<c r="A1" t="inlineStr"><is><t>A01-B02</t></is></c>`},
+	} {
+		want := `<row r="1">` + tc.want + "</row>"
+		if got := xlsxRow(1, []string{tc.cell}); got != want {
+			t.Errorf("%q: %v, want %v", tc.cell, got, want)
+		}
+	}
+}
@@ -0,0 +1,295 @@
+package warehouse
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+)
+
+// PlanVersion is the version of the plan schema written by NewPlan,
+// described by schema/plan.v1.schema.json
+const PlanVersion = 1
+
+// Step types of a trip
+const (
+	StepWalk = "walk"
+	StepPick = "pick"
+)
+
+// Plan is the versioned output of the planners: the trips with their steps,
+// their totals and the way the plan was made. Lateness tells when every order
+// is finished when the orders have time windows, and Flows the zones the tote
+// of every order passes through in zone picking.
+type Plan struct {
+	Version  int          `json:"version"`
+	Metadata PlanMetadata `json:"metadata"`
+	Trips    []PlanTrip   `json:"trips"`
+	Totals   PlanTotals   `json:"totals"`
+	Lateness []Lateness   `json:"lateness,omitempty"`
+	Flows    []ZoneFlow   `json:"flows,omitempty"`
+}
+
+// PlanMetadata tells how a plan was made. LowerBound is the sum of the lower bounds
+// of the trips' lengths, 0 when not computed. Wave is the number of the wave
+// of a plan of an order stream, counted from 1.
+type PlanMetadata struct {
+	Algorithm  string    `json:"algorithm"`
+	Objective  string    `json:"objective,omitempty"`
+	Created    time.Time `json:"created"`
+	Runtime    float64   `json:"runtime_seconds"`
+	LowerBound float64   `json:"lower_bound,omitempty"`
+	Wave       int       `json:"wave,omitempty"`
+}
+
+// PlanPoint is a cell of the grid in the plan schema. Name is its location name,
+// left out of the cells of the paths.
+type PlanPoint struct {
+	X     int    `json:"x"`
+	Y     int    `json:"y"`
+	Floor int    `json:"floor,omitempty"`
+	Name  string `json:"name,omitempty"`
+}
+
+// PlanTrip is one trip of a plan, walked from Start to End.
+// Batch is the index of the batch of orders the trip belongs to.
+// Worker is the id of the worker walking it in a fleet plan, nil otherwise,
+// and Zone the zone it is picked in with zone picking.
+type PlanTrip struct {
+	Batch  int        `json:"batch"`
+	Worker *int       `json:"worker,omitempty"`
+	Zone   string     `json:"zone,omitempty"`
+	Start  PlanPoint  `json:"start"`
+	End    PlanPoint  `json:"end"`
+	Steps  []PlanStep `json:"steps"`
+	Totals PlanTotals `json:"totals"`
+}
+
+// PlanStep is a walk along Path of Length meters or the pick of SKU for an order.
+// A pick is made at Access in the aisle from the shelf at Location;
+// Weight is nil when the weight of the product is unknown.
+type PlanStep struct {
+	Type     string      `json:"type"`
+	Path     []PlanPoint `json:"path,omitempty"`
+	Length   float64     `json:"length,omitempty"`
+	SKU      int         `json:"sku,omitempty"`
+	OrderID  int         `json:"order_id,omitempty"`
+	Location *PlanPoint  `json:"location,omitempty"`
+	Access   *PlanPoint  `json:"access,omitempty"`
+	Level    int         `json:"level,omitempty"`
+	Weight   *float64    `json:"weight,omitempty"`
+}
+
+// PlanTotals sums up a trip or a plan. Length is the sum of the lengths of the walks,
+// in meters. Effort is the weight carried on every leg times its travel cost in the
+// pathInfo the plan is built from, which as built by BuildPathInfo includes the turn
+// penalties and direction factors. MissingWeight is set when the weight of some
+// product is unknown, so Weight and Effort are lower bounds.
+type PlanTotals struct {
+	Trips         int     `json:"trips"`
+	Items         int     `json:"items"`
+	Length        float64 `json:"length"`
+	Effort        float64 `json:"effort"`
+	Weight        float64 `json:"weight"`
+	MissingWeight bool    `json:"missing_weight,omitempty"`
+}
+
+// add returns the totals together with t
+func (s PlanTotals) add(t PlanTotals) PlanTotals {
+	return PlanTotals{
+		Trips:         s.Trips + t.Trips,
+		Items:         s.Items + t.Items,
+		Length:        s.Length + t.Length,
+		Effort:        s.Effort + t.Effort,
+		Weight:        s.Weight + t.Weight,
+		MissingWeight: s.MissingWeight || t.MissingWeight,
+	}
+}
+
+// MarshalJSON writes the SKU and the order id of a pick even when they are 0,
+// which are left out of walks
+func (s PlanStep) MarshalJSON() ([]byte, error) {
+	type step PlanStep
+	if s.Type != StepPick {
+		return json.Marshal(step(s))
+	}
+	return json.Marshal(struct {
+		step
+		SKU     int `json:"sku"`
+		OrderID int `json:"order_id"`
+	}{step(s), s.SKU, s.OrderID})
+}
+
+// ToPlanPoint returns the point in the plan schema with its location name
+func ToPlanPoint(p Point) PlanPoint {
+	return PlanPoint{p.X, p.Y, p.F, LocationName(p)}
+}
+
+// Point returns the point of the grid
+func (p PlanPoint) Point() Point {
+	return Point{X: p.X, Y: p.Y, F: p.Floor}
+}
+
+func toPlanPath(path Path) []PlanPoint {
+	var pp []PlanPoint
+	for _, p := range path {
+		pp = append(pp, PlanPoint{X: p.X, Y: p.Y, Floor: p.F})
+	}
+	return pp
+}
+
+// NewPlanTrip returns the trip of a sequenced Order walked from start to end
+func NewPlanTrip(o Order, start, end Point, m map[int]Product, r *Router, pathInfo map[Point]map[Point]float64) PlanTrip {
+	trip := PlanTrip{Start: ToPlanPoint(start), End: ToPlanPoint(end)}
+	walk := func(src, dest Point) {
+		if path := r.FindPath(src, dest); len(path) > 1 {
+			length := PathLength(path)
+			trip.Steps = append(trip.Steps, PlanStep{Type: StepWalk, Path: toPlanPath(path), Length: length})
+			trip.Totals.Length += length
+		}
+	}
+	pos := start
+	for _, item := range o {
+		prod := m[item.ProdID]
+		dest := FindDest(pos, prod)
+		walk(pos, dest)
+		location, access := ToPlanPoint(prod.Pos), ToPlanPoint(dest)
+		step := PlanStep{Type: StepPick, SKU: item.ProdID, OrderID: item.OrderID, Location: &location, Access: &access, Level: prod.Level}
+		if prod.wAvail {
+			w := prod.w
+			step.Weight = &w
+			trip.Totals.Weight += w
+		} else {
+			trip.Totals.MissingWeight = true
+		}
+		trip.Steps = append(trip.Steps, step)
+		pos = dest
+	}
+	walk(pos, end)
+	trip.Totals.Trips = 1
+	trip.Totals.Items = len(o)
+	if len(o) > 0 {
+		trip.Totals.Effort, _ = RouteEffort(o, start, end, m, pathInfo)
+	}
+	return trip
+}
+
+// routeTrips returns the trips of the RouteOrder
+func routeTrips(ro RouteOrder, m map[int]Product, r *Router, pathInfo map[Point]map[Point]float64) []PlanTrip {
+	var trips []PlanTrip
+	for i, o := range ro.Orders {
+		start, end := ro.Start, ro.End
+		if i < len(ro.Starts) {
+			start, end = ro.Starts[i], ro.Ends[i]
+		}
+		trips = append(trips, NewPlanTrip(o, start, end, m, r, pathInfo))
+	}
+	return trips
+}
+
+// add appends the trip to the plan and sums it up in the totals
+func (plan *Plan) add(trip PlanTrip) {
+	plan.Trips = append(plan.Trips, trip)
+	plan.Totals = plan.Totals.add(trip.Totals)
+}
+
+// NewPlan returns the plan of the routes, one batch per RouteOrder
+func NewPlan(ros []RouteOrder, m map[int]Product, r *Router, pathInfo map[Point]map[Point]float64, meta PlanMetadata) Plan {
+	plan := Plan{Version: PlanVersion, Metadata: meta, Trips: []PlanTrip{}}
+	for b, ro := range ros {
+		for _, trip := range routeTrips(ro, m, r, pathInfo) {
+			trip.Batch = b
+			plan.add(trip)
+		}
+	}
+	return plan
+}
+
+// NewFleetPlan returns the plan of the workers' routes, one batch per trip,
+// every trip telling its worker
+func NewFleetPlan(plans []WorkerPlan, m map[int]Product, r *Router, pathInfo map[Point]map[Point]float64, meta PlanMetadata) Plan {
+	plan := Plan{Version: PlanVersion, Metadata: meta, Trips: []PlanTrip{}}
+	for _, wp := range plans {
+		id := wp.Worker.ID
+		for _, trip := range routeTrips(wp.Route, m, r, pathInfo) {
+			trip.Batch = len(plan.Trips)
+			trip.Worker = &id
+			plan.add(trip)
+		}
+	}
+	return plan
+}
+
+// NewZonePlan returns the plan of zone picking, one batch per part of an order
+// picked in a zone, with the flows of the totes
+func NewZonePlan(routes []ZoneRoute, flows []ZoneFlow, m map[int]Product, r *Router, pathInfo map[Point]map[Point]float64, meta PlanMetadata) Plan {
+	plan := Plan{Version: PlanVersion, Metadata: meta, Trips: []PlanTrip{}, Flows: flows}
+	for _, zr := range routes {
+		for _, trip := range routeTrips(zr.Route, m, r, pathInfo) {
+			trip.Batch = len(plan.Trips)
+			trip.Zone = zr.Zone
+			plan.add(trip)
+		}
+	}
+	return plan
+}
+
+// Routes returns the sequenced orders of the plan grouped by batch, as RouteOrders
+func (plan Plan) Routes(m map[int]Product, r *Router) []RouteOrder {
+	var ros []RouteOrder
+	var orders []Order
+	var starts, ends []Point
+	flush := func() {
+		if len(orders) > 0 {
+			ros = append(ros, Orders2RoutesDepots(orders, starts, ends, m, r))
+		}
+		orders, starts, ends = nil, nil, nil
+	}
+	for i, trip := range plan.Trips {
+		if i > 0 && trip.Batch != plan.Trips[i-1].Batch {
+			flush()
+		}
+		orders = append(orders, trip.Order())
+		starts = append(starts, trip.Start.Point())
+		ends = append(ends, trip.End.Point())
+	}
+	flush()
+	return ros
+}
+
+// Order returns the picks of the trip as an Order
+func (trip PlanTrip) Order() Order {
+	var o Order
+	for _, step := range trip.Steps {
+		if step.Type == StepPick {
+			o = append(o, Item{step.SKU, step.OrderID})
+		}
+	}
+	return o
+}
+
+// DecodePlan returns the plan read from r, which must be of a known version
+func DecodePlan(r io.Reader) (Plan, error) {
+	var plan Plan
+	d := json.NewDecoder(r)
+	d.DisallowUnknownFields()
+	if err := d.Decode(&plan); err != nil {
+		return plan, err
+	}
+	if plan.Version != PlanVersion {
+		return plan, fmt.Errorf("unknown plan version %v", plan.Version)
+	}
+	for i, trip := range plan.Trips {
+		for j, step := range trip.Steps {
+			switch {
+			case step.Type == StepWalk && len(step.Path) == 0:
+				return plan, fmt.Errorf("trip %v step %v: walk without a path", i, j)
+			case step.Type == StepPick && step.Location == nil:
+				return plan, fmt.Errorf("trip %v step %v: pick without a location", i, j)
+			case step.Type != StepWalk && step.Type != StepPick:
+				return plan, fmt.Errorf("trip %v step %v: unknown step type %q", i, j, step.Type)
+			}
+		}
+	}
+	return plan, nil
+}
@@ -0,0 +1,138 @@
+package warehouse
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"reflect"
+	"testing"
+)
+
+// testProducts sets a warehouse of 4 by 4 shelves and returns products 0, 1 and 2
+// on it, product 1 with its weight
+func testProducts(t *testing.T) map[int]Product {
+	t.Helper()
+	l := DefaultLayout
+	l.MaxX, l.MaxY = 8, 8
+	SetLayout(l)
+	t.Cleanup(func() { SetLayout(DefaultLayout) })
+	grid := writeInput(t, "grid.csv", "0, 0, 0\n1, 1, 2, 1\n2, 3, 3\n")
+	return ParseProductInfo(grid, map[int][]float64{1: {1, 1, 1, 2.5}})
+}
+
+func TestPlanRoundTrip(t *testing.T) {
+	m := testProducts(t)
+	r := NewRouter(TravelModel{})
+	pathInfo := BuildPathInfo("", r)
+	start, end := Point{0, 0, 0}, Point{8, 8, 0}
+	orders := []Order{{{2, 0}, {0, 0}}, {{1, 5}}}
+	ro := Orders2Routes(orders, start, end, m, r)
+	plan := NewPlan([]RouteOrder{ro}, m, r, pathInfo, PlanMetadata{Algorithm: "nni", Objective: "cost"})
+
+	for i, trip := range plan.Trips {
+		var walked float64
+		for _, step := range trip.Steps {
+			walked += step.Length
+		}
+		if math.Abs(trip.Totals.Length-walked) > 1e-9 {
+			t.Errorf("trip %v: length %v, the walks sum up to %v", i, trip.Totals.Length, walked)
+		}
+		if want := RouteLength(orders[i], start, end, m, r); math.Abs(trip.Totals.Length-want) > 1e-9 {
+			t.Errorf("trip %v: length %v, RouteLength %v", i, trip.Totals.Length, want)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(plan); err != nil {
+		t.Fatal(err)
+	}
+	data := buf.String()
+	got, err := DecodePlan(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// times keep their instant but not their location
+	got.Metadata.Created = plan.Metadata.Created
+	if !reflect.DeepEqual(got, plan) {
+		t.Errorf("decoded plan differs:\n%+v\nwant\n%+v", got, plan)
+	}
+	if !reflect.DeepEqual(got.Trips[0].Order(), orders[0]) {
+		t.Errorf("order %v, want %v: SKU 0 and order id 0 must be kept", got.Trips[0].Order(), orders[0])
+	}
+	if !bytes.Contains([]byte(data), []byte(`"sku":0,"order_id":0`)) {
+		t.Errorf("pick of SKU 0 for order 0 is not written: %s", data)
+	}
+}
+
+func TestFleetAndZonePlans(t *testing.T) {
+	m := testProducts(t)
+	r := NewRouter(TravelModel{})
+	pathInfo := BuildPathInfo("", r)
+	start := Point{0, 0, 0}
+	plans := []WorkerPlan{
+		{Worker: Worker{ID: 0}, Route: Orders2Routes([]Order{{{0, 1}}, {{2, 2}}}, start, start, m, r)},
+		{Worker: Worker{ID: 4}, Route: Orders2Routes([]Order{{{1, 3}}}, start, start, m, r)},
+	}
+	fleet := NewFleetPlan(plans, m, r, pathInfo, PlanMetadata{})
+	var workers, batches []int
+	for _, trip := range fleet.Trips {
+		if trip.Worker == nil {
+			t.Fatalf("trip %+v has no worker", trip)
+		}
+		workers = append(workers, *trip.Worker)
+		batches = append(batches, trip.Batch)
+	}
+	if !reflect.DeepEqual(workers, []int{0, 0, 4}) || !reflect.DeepEqual(batches, []int{0, 1, 2}) {
+		t.Errorf("workers %v and batches %v, want [0 0 4] and [0 1 2]", workers, batches)
+	}
+	if fleet.Totals.Trips != 3 || fleet.Totals.Items != 3 {
+		t.Errorf("totals %+v, want 3 trips of 3 items", fleet.Totals)
+	}
+
+	flows := []ZoneFlow{{OrderID: 1, Zones: []string{"north"}}}
+	zones := NewZonePlan([]ZoneRoute{{"north", Orders2Routes([]Order{{{0, 1}}}, start, start, m, r)}}, flows, m, r, pathInfo, PlanMetadata{})
+	if len(zones.Trips) != 1 || zones.Trips[0].Zone != "north" || !reflect.DeepEqual(zones.Flows, flows) {
+		t.Errorf("zone plan %+v, want one trip in north with its flow", zones)
+	}
+}
+
+func TestEvaluatePlanWithoutDrift(t *testing.T) {
+	m := testProducts(t)
+	r := NewRouter(TravelModel{TurnPenalty: 3})
+	pathInfo := BuildPathInfo("", r)
+	start := Point{0, 0, 0}
+	ro := Orders2Routes([]Order{{{2, 1}, {1, 1}, {0, 1}}}, start, start, m, r)
+	plan := NewPlan([]RouteOrder{ro}, m, r, pathInfo, PlanMetadata{})
+	for _, e := range EvaluatePlan(plan, m, r, pathInfo) {
+		if len(e.Drift) != 0 || math.Abs(e.Length-e.Planned.Length) > 1e-9 {
+			t.Errorf("trip %v: now %v m with drift %v, planned %v m", e.Trip, e.Length, e.Drift, e.Planned.Length)
+		}
+	}
+}
+
+func TestEvaluatePlanDrift(t *testing.T) {
+	m := testProducts(t)
+	r := NewRouter(TravelModel{})
+	pathInfo := BuildPathInfo("", r)
+	start := Point{0, 0, 0}
+	plan := NewPlan([]RouteOrder{Orders2Routes([]Order{{{2, 1}, {1, 1}, {0, 1}}}, start, start, m, r)}, m, r, pathInfo, PlanMetadata{})
+	up, moved := m[1], m[2]
+	up.Level = 2
+	moved.Pos = Point{5, 1, 0}
+	m[1], m[2] = up, moved
+	delete(m, 0)
+	want := map[int]string{
+		0: "item id 0 planned at A01-B01R no longer exists",
+		1: "item id 1 moved from A02-B03R-L1 to A02-B03R-L2",
+		2: "item id 2 moved from A04-B04R to A03-B01R",
+	}
+	got := make(map[int]string)
+	for _, e := range EvaluatePlan(plan, m, r, pathInfo) {
+		for _, d := range e.Drift {
+			got[d.SKU] = d.String()
+		}
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("drift %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,211 @@
+package warehouse
+
+import (
+	"fmt"
+	"log"
+	"math"
+)
+
+// Rule says that items of class Before are picked before items of class After.
+// "*" stands for any other class, e.g. Rule{"*", "frozen"} picks frozen goods last.
+type Rule struct {
+	Before, After string
+}
+
+// rules are the precedence rules the optimizers respect
+var rules []Rule
+
+// SetRules sets the precedence rules the optimizers respect
+func SetRules(r []Rule) {
+	rules = r
+}
+
+// LoadRules returns the precedence rules from a config file of "before, after" class lines
+func LoadRules(path string) []Rule {
+	var r []Rule
+	records, ok := readConfig(path)
+	if !ok {
+		return r
+	}
+	for _, rec := range records {
+		if len(rec) < 2 {
+			log.Fatalf("Rule %v needs two classes.", rec)
+		}
+		r = append(r, Rule{rec[0], rec[1]})
+	}
+	return r
+}
+
+// ParseClassInfo returns the handling class of every item from a file of "id, class" lines
+func ParseClassInfo(path string) map[int]string {
+	classes := make(map[int]string)
+	records, ok := readConfig(path)
+	if !ok {
+		return classes
+	}
+	for _, rec := range records {
+		if len(rec) < 2 {
+			log.Fatalf("Item class %v needs an id and a class.", rec)
+		}
+		classes[configInt(rec, 0)] = rec[1]
+	}
+	return classes
+}
+
+// AssignClasses sets the handling class of the products
+func AssignClasses(m map[int]Product, classes map[int]string) {
+	for id, class := range classes {
+		if prod, ok := m[id]; ok {
+			prod.Class = class
+			m[id] = prod
+		}
+	}
+}
+
+// precedes reports whether a rule makes product a come before product b
+func precedes(a, b Product) bool {
+	if a.Class == b.Class {
+		return false
+	}
+	matches := func(pattern, class string) bool {
+		return pattern == class || pattern == "*"
+	}
+	for _, r := range rules {
+		if matches(r.Before, a.Class) && matches(r.After, b.Class) {
+			return true
+		}
+	}
+	return false
+}
+
+// constrained reports whether any rule applies between the items of the order
+func constrained(o Order, m map[int]Product) bool {
+	for _, i := range o {
+		for _, j := range o {
+			if precedes(m[i.ProdID], m[j.ProdID]) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// eligible reports whether no remaining product has to be picked before prod
+func eligible(prod Product, remaining []Product) bool {
+	for _, r := range remaining {
+		if precedes(r, prod) {
+			return false
+		}
+	}
+	return true
+}
+
+// Violation is a pair of items picked against a precedence rule
+type Violation struct {
+	Early, Late Item
+}
+
+func (v Violation) String() string {
+	return fmt.Sprintf("%v is picked before %v", v.Early, v.Late)
+}
+
+// PrecedenceViolations returns the pairs of items of the Order picked against the rules
+func PrecedenceViolations(o Order, m map[int]Product) []Violation {
+	var violations []Violation
+	for i := range o {
+		for _, late := range o[i+1:] {
+			if precedes(m[late.ProdID], m[o[i].ProdID]) {
+				violations = append(violations, Violation{o[i], late})
+			}
+		}
+	}
+	return violations
+}
+
+// precedenceOrderOptimizer returns the Order by finding the nearest neighbour
+// among the items whose predecessors are all picked, trying every eligible first item.
+// When rules form a cycle, the nearest item is taken and the violation remains.
+func precedenceOrderOptimizer(o Order, start, end Point, m map[int]Product, pathInfo map[Point]map[Point]float64) Order {
+	var prods []Product
+	for _, p := range o {
+		prod := m[p.ProdID]
+		prod.OrderID = p.OrderID
+		prods = append(prods, prod)
+	}
+	var firsts []int
+	for i, prod := range prods {
+		if eligible(prod, prods) {
+			firsts = append(firsts, i)
+		}
+	}
+	if len(firsts) == 0 {
+		for i := range prods {
+			firsts = append(firsts, i)
+		}
+	}
+	var newOrder Order
+	minTotal := math.Inf(1)
+	minViolations := len(prods) * len(prods)
+	for _, first := range firsts {
+		ps := make([]Product, len(prods))
+		copy(ps, prods)
+		src := start
+		var order Order
+		next := first
+		for len(ps) > 0 {
+			if len(order) > 0 {
+				next = -1
+				min := math.Inf(1)
+				for i, prod := range ps {
+					length := pathInfo[src][FindDest(src, prod)] + reachCost(prod)
+					if length < min && eligible(prod, ps) {
+						min = length
+						next = i
+					}
+				}
+				if next < 0 {
+					// cycle of rules: take the nearest item anyway
+					for i, prod := range ps {
+						length := pathInfo[src][FindDest(src, prod)] + reachCost(prod)
+						if next < 0 || length < min {
+							min = length
+							next = i
+						}
+					}
+				}
+			}
+			src = FindDest(src, ps[next])
+			order = append(order, Item{ps[next].id, ps[next].OrderID})
+			ps = append(ps[:next], ps[next+1:]...)
+		}
+		violations := len(PrecedenceViolations(order, m))
+		length := RouteCost(order, start, end, m, pathInfo)
+		if violations < minViolations || (violations == minViolations && length < minTotal) {
+			minViolations = violations
+			minTotal = length
+			newOrder = order
+		}
+	}
+	return newOrder
+}
+
+// pickable reports whether every item of the Order that has to be picked before o[i]
+// is among the picked indices
+func pickable(o Order, i int, picked []int, m map[int]Product) bool {
+	for j, item := range o {
+		if !precedes(m[item.ProdID], m[o[i].ProdID]) {
+			continue
+		}
+		found := false
+		for _, k := range picked {
+			if k == j {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
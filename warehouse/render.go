@@ -0,0 +1,311 @@
+package warehouse
+
+import (
+	"bufio"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"log"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// RenderScale is the number of pixels per meter of the rendered maps
+const RenderScale = 20.0
+
+// tripColors are the colours of the trips, reused when there are more trips
+var tripColors = []color.RGBA{
+	{0xe6, 0x19, 0x4b, 0xff}, {0x3c, 0xb4, 0x4b, 0xff}, {0x43, 0x63, 0xd8, 0xff}, {0xf5, 0x82, 0x31, 0xff},
+	{0x91, 0x1e, 0xb4, 0xff}, {0x42, 0xd4, 0xf4, 0xff}, {0xf0, 0x32, 0xe6, 0xff}, {0x80, 0x80, 0x00, 0xff},
+	{0x00, 0x80, 0x80, 0xff}, {0x9a, 0x63, 0x24, 0xff}, {0x80, 0x00, 0x00, 0xff}, {0x00, 0x00, 0x75, 0xff},
+}
+
+var (
+	backgroundColor = color.RGBA{0xff, 0xff, 0xff, 0xff}
+	shelfColor      = color.RGBA{0xbb, 0xbb, 0xbb, 0xff}
+	startColor      = color.RGBA{0x22, 0xaa, 0x22, 0xff}
+	endColor        = color.RGBA{0xcc, 0x22, 0x22, 0xff}
+	labelColor      = color.RGBA{0xff, 0xff, 0xff, 0xff}
+)
+
+// scene is a map drawn in pixels with the origin at the top left.
+// err is the first point that could not be drawn.
+type scene struct {
+	width, height float64
+	rects         []sceneRect
+	lines         []sceneLine
+	marks         []sceneMark
+	err           error
+}
+
+type sceneRect struct {
+	x, y, w, h float64
+	fill       color.RGBA
+}
+
+type sceneLine struct {
+	points [][2]float64
+	stroke color.RGBA
+	width  float64
+}
+
+// sceneMark is a filled circle with an optional number on it
+type sceneMark struct {
+	x, y, r float64
+	fill    color.RGBA
+	label   string
+}
+
+// pixel returns the center of the point in pixels, Y growing downwards
+func (s *scene) pixel(p Point) [2]float64 {
+	x, y, err := layout.Meters(p)
+	if err != nil && s.err == nil {
+		s.err = err
+	}
+	return [2]float64{x * RenderScale, s.height - y*RenderScale}
+}
+
+// newScene returns the map of floor f: the shelves, the depots and every trip of the
+// routes with its path and numbered picks in its own colour. Starts are drawn as
+// green squares and ends as red dots. It fails on points out of the warehouse.
+func newScene(ros []RouteOrder, d Depots, m map[int]Product, f int) (*scene, error) {
+	last := Point{X: layout.MaxX, Y: layout.MaxY, F: f}
+	x, y, err := layout.Meters(last)
+	if err != nil {
+		return nil, err
+	}
+	s := &scene{
+		width:  (x + layout.ColumnWidth(layout.MaxX)/2) * RenderScale,
+		height: (y + layout.RowWidth(layout.MaxY)/2) * RenderScale,
+	}
+	for i := 1; i <= layout.MaxX; i += 2 {
+		for j := 1; j <= layout.MaxY; j += 2 {
+			c := s.pixel(Point{X: i, Y: j})
+			w, h := layout.ColumnWidth(i)*RenderScale, layout.RowWidth(j)*RenderScale
+			s.rects = append(s.rects, sceneRect{c[0] - w/2, c[1] - h/2, w, h, shelfColor})
+		}
+	}
+	r := RenderScale * 0.3
+	trip := 0
+	for _, ro := range ros {
+		for i, o := range ro.Orders {
+			stroke := tripColors[trip%len(tripColors)]
+			trip++
+			if i < len(ro.Paths) {
+				var points [][2]float64
+				flush := func() {
+					if len(points) > 1 {
+						s.lines = append(s.lines, sceneLine{points, stroke, RenderScale * 0.15})
+					}
+					points = nil
+				}
+				for _, p := range ro.Paths[i] {
+					if p.F != f {
+						flush()
+						continue
+					}
+					points = append(points, s.pixel(p))
+				}
+				flush()
+			}
+			for n, item := range o {
+				prod := m[item.ProdID]
+				if i < len(ro.Products) && n < len(ro.Products[i]) {
+					prod = ro.Products[i][n]
+				}
+				if prod.Pos.F != f {
+					continue
+				}
+				c := s.pixel(prod.Pos)
+				s.marks = append(s.marks, sceneMark{c[0], c[1], r * 1.4, stroke, fmt.Sprint(n + 1)})
+			}
+		}
+	}
+	// every depot is drawn once, however many trips use it
+	var starts, ends []Point
+	for _, p := range d.Starts {
+		starts = appendPoint(starts, p)
+	}
+	for _, p := range d.Ends {
+		ends = appendPoint(ends, p)
+	}
+	for _, ro := range ros {
+		for _, p := range append([]Point{ro.Start}, ro.Starts...) {
+			starts = appendPoint(starts, p)
+		}
+		for _, p := range append([]Point{ro.End}, ro.Ends...) {
+			ends = appendPoint(ends, p)
+		}
+	}
+	for _, p := range starts {
+		if p.F == f {
+			c := s.pixel(p)
+			s.rects = append(s.rects, sceneRect{c[0] - r, c[1] - r, 2 * r, 2 * r, startColor})
+		}
+	}
+	for _, p := range ends {
+		if p.F == f {
+			c := s.pixel(p)
+			s.marks = append(s.marks, sceneMark{c[0], c[1], r, endColor, ""})
+		}
+	}
+	return s, s.err
+}
+
+func svgColor(c color.RGBA) string {
+	return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+}
+
+// writeSVG writes the scene as SVG
+func (s *scene) writeSVG(w io.Writer) error {
+	b := bufio.NewWriter(w)
+	fmt.Fprintf(b, `<svg xmlns="http://www.w3.org/2000/svg" width="%.0f" height="%.0f" viewBox="0 0 %.1f %.1f">`+"\n",
+		s.width, s.height, s.width, s.height)
+	fmt.Fprintf(b, `<rect width="100%%" height="100%%" fill="%v"/>`+"\n", svgColor(backgroundColor))
+	for _, r := range s.rects {
+		fmt.Fprintf(b, `<rect x="%.1f" y="%.1f" width="%.1f" height="%.1f" fill="%v"/>`+"\n", r.x, r.y, r.w, r.h, svgColor(r.fill))
+	}
+	for _, l := range s.lines {
+		var pts []string
+		for _, p := range l.points {
+			pts = append(pts, fmt.Sprintf("%.1f,%.1f", p[0], p[1]))
+		}
+		fmt.Fprintf(b, `<polyline points="%v" fill="none" stroke="%v" stroke-width="%.1f" stroke-linejoin="round" stroke-opacity="0.8"/>`+"\n",
+			strings.Join(pts, " "), svgColor(l.stroke), l.width)
+	}
+	for _, mk := range s.marks {
+		fmt.Fprintf(b, `<circle cx="%.1f" cy="%.1f" r="%.1f" fill="%v"/>`+"\n", mk.x, mk.y, mk.r, svgColor(mk.fill))
+		if mk.label != "" {
+			fmt.Fprintf(b, `<text x="%.1f" y="%.1f" font-family="sans-serif" font-size="%.1f" fill="%v" text-anchor="middle" dominant-baseline="central">%v</text>`+"\n",
+				mk.x, mk.y, mk.r*1.2, svgColor(labelColor), mk.label)
+		}
+	}
+	fmt.Fprintln(b, "</svg>")
+	return b.Flush()
+}
+
+// digitFont is a 3x5 bitmap of the digits, one row of 3 bits per line
+var digitFont = [10][5]uint8{
+	{7, 5, 5, 5, 7}, {2, 6, 2, 2, 7}, {7, 1, 7, 4, 7}, {7, 1, 7, 1, 7}, {5, 5, 7, 1, 1},
+	{7, 4, 7, 1, 7}, {7, 4, 7, 5, 7}, {7, 1, 1, 1, 1}, {7, 5, 7, 5, 7}, {7, 5, 7, 1, 7},
+}
+
+func fillRect(img *image.RGBA, x0, y0, x1, y1 float64, c color.RGBA) {
+	for y := int(math.Round(y0)); y < int(math.Round(y1)); y++ {
+		for x := int(math.Round(x0)); x < int(math.Round(x1)); x++ {
+			img.SetRGBA(x, y, c)
+		}
+	}
+}
+
+func fillCircle(img *image.RGBA, cx, cy, r float64, c color.RGBA) {
+	for y := int(cy - r); y <= int(cy+r)+1; y++ {
+		for x := int(cx - r); x <= int(cx+r)+1; x++ {
+			dx, dy := float64(x)+0.5-cx, float64(y)+0.5-cy
+			if dx*dx+dy*dy <= r*r {
+				img.SetRGBA(x, y, c)
+			}
+		}
+	}
+}
+
+// drawLabel draws the digits of label centered on cx, cy with pixels of size px
+func drawLabel(img *image.RGBA, label string, cx, cy, px float64, c color.RGBA) {
+	width := float64(4*len(label)-1) * px
+	x0, y0 := cx-width/2, cy-2.5*px
+	for i, ch := range label {
+		if ch < '0' || ch > '9' {
+			continue
+		}
+		for row, bits := range digitFont[ch-'0'] {
+			for col := 0; col < 3; col++ {
+				if bits&(4>>uint(col)) != 0 {
+					x := x0 + float64(4*i+col)*px
+					y := y0 + float64(row)*px
+					fillRect(img, x, y, x+px, y+px, c)
+				}
+			}
+		}
+	}
+}
+
+// writePNG writes the scene as PNG
+func (s *scene) writePNG(w io.Writer) error {
+	img := image.NewRGBA(image.Rect(0, 0, int(math.Ceil(s.width)), int(math.Ceil(s.height))))
+	fillRect(img, 0, 0, s.width, s.height, backgroundColor)
+	for _, r := range s.rects {
+		fillRect(img, r.x, r.y, r.x+r.w, r.y+r.h, r.fill)
+	}
+	for _, l := range s.lines {
+		for i := 1; i < len(l.points); i++ {
+			a, b := l.points[i-1], l.points[i]
+			steps := int(math.Max(math.Abs(b[0]-a[0]), math.Abs(b[1]-a[1]))) + 1
+			for k := 0; k <= steps; k++ {
+				t := float64(k) / float64(steps)
+				fillCircle(img, a[0]+(b[0]-a[0])*t, a[1]+(b[1]-a[1])*t, l.width/2, l.stroke)
+			}
+		}
+	}
+	for _, mk := range s.marks {
+		fillCircle(img, mk.x, mk.y, mk.r, mk.fill)
+		if mk.label != "" {
+			// the digits fit in the circle, 5 pixels high and 4 wide each
+			px := math.Max(1, math.Floor(math.Min(1.2*mk.r/5, 1.6*mk.r/float64(4*len(mk.label)-1))))
+			drawLabel(img, mk.label, mk.x, mk.y, px, labelColor)
+		}
+	}
+	return png.Encode(w, img)
+}
+
+// RenderSVG writes the map of floor f with the trips of the routes as SVG
+func RenderSVG(w io.Writer, ros []RouteOrder, d Depots, m map[int]Product, f int) error {
+	s, err := newScene(ros, d, m, f)
+	if err != nil {
+		return err
+	}
+	return s.writeSVG(w)
+}
+
+// RenderPNG writes the map of floor f with the trips of the routes as PNG
+func RenderPNG(w io.Writer, ros []RouteOrder, d Depots, m map[int]Product, f int) error {
+	s, err := newScene(ros, d, m, f)
+	if err != nil {
+		return err
+	}
+	return s.writePNG(w)
+}
+
+// RenderMap writes the map with the trips of the routes to the file at path,
+// as SVG or PNG by its extension. With several floors every floor goes to its
+// own file, named like map_F1.svg.
+func RenderMap(path string, ros []RouteOrder, d Depots, m map[int]Product) {
+	ext := strings.ToLower(filepath.Ext(path))
+	render := RenderSVG
+	switch ext {
+	case ".svg":
+	case ".png":
+		render = RenderPNG
+	default:
+		log.Fatalf("Cannot render to %q: use a .svg or a .png file.", path)
+	}
+	for f := 0; f < layout.Floors; f++ {
+		name := path
+		if layout.Floors > 1 {
+			name = fmt.Sprintf("%v_F%d%v", strings.TrimSuffix(path, filepath.Ext(path)), f, filepath.Ext(path))
+		}
+		file, err := os.Create(name)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if err := render(file, ros, d, m, f); err != nil {
+			log.Fatal(err)
+		}
+		if err := file.Close(); err != nil {
+			log.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package warehouse
+
+import (
+	"bytes"
+	"image/color"
+	"image/png"
+	"strings"
+	"testing"
+)
+
+// testRoutes returns a trip picking products 0 and 2 from the origin to the far corner
+func testRoutes(t *testing.T) ([]RouteOrder, map[int]Product) {
+	t.Helper()
+	m := testProducts(t)
+	return []RouteOrder{Orders2Routes([]Order{{{0, 1}, {2, 1}}}, Point{0, 0, 0}, Point{8, 8, 0}, m, NewRouter(TravelModel{}))}, m
+}
+
+func TestRenderSVG(t *testing.T) {
+	ros, m := testRoutes(t)
+	var b bytes.Buffer
+	if err := RenderSVG(&b, ros, Depots{}, m, 0); err != nil {
+		t.Fatal(err)
+	}
+	svg := b.String()
+	// 9 cells of 1 m are 180 pixels; the origin is at the bottom left
+	for _, want := range []string{
+		`width="180" height="180"`,
+		`<rect x="20.0" y="140.0" width="20.0" height="20.0" fill="#bbbbbb"/>`,
+		`<rect x="4.0" y="164.0" width="12.0" height="12.0" fill="#22aa22"/>`,
+		`<circle cx="170.0" cy="10.0" r="6.0" fill="#cc2222"/>`,
+		`<circle cx="30.0" cy="150.0" r="8.4" fill="#e6194b"/>`,
+		`<circle cx="150.0" cy="30.0" r="8.4" fill="#e6194b"/>`,
+		`dominant-baseline="central">2</text>`,
+	} {
+		if !strings.Contains(svg, want) {
+			t.Errorf("SVG without %v:\n%v", want, svg)
+		}
+	}
+	for elem, want := range map[string]int{"<rect": 1 + 16 + 1, "<polyline": 1, "<circle": 2 + 1, "<text": 2} {
+		if got := strings.Count(svg, elem); got != want {
+			t.Errorf("%v %v elements, want %v", got, elem, want)
+		}
+	}
+}
+
+func TestRenderPNG(t *testing.T) {
+	ros, m := testRoutes(t)
+	var b bytes.Buffer
+	if err := RenderPNG(&b, ros, Depots{}, m, 0); err != nil {
+		t.Fatal(err)
+	}
+	img, err := png.Decode(&b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if size := img.Bounds().Size(); size.X != 180 || size.Y != 180 {
+		t.Fatalf("image of %v, want 180x180", size)
+	}
+	for _, tc := range []struct {
+		name string
+		x, y int
+		want color.RGBA
+	}{
+		{"aisle", 90, 10, backgroundColor},
+		{"shelf", 110, 70, shelfColor},
+		{"start beside the path", 5, 175, startColor},
+		{"end", 170, 10, endColor},
+		{"first pick beside its number", 37, 150, tripColors[0]},
+	} {
+		if got := color.RGBAModel.Convert(img.At(tc.x, tc.y)).(color.RGBA); got != tc.want {
+			t.Errorf("%v at (%v, %v) is %v, want %v", tc.name, tc.x, tc.y, got, tc.want)
+		}
+	}
+}
+
+func TestRenderOutOfTheWarehouse(t *testing.T) {
+	ros, m := testRoutes(t)
+	ros[0].End = Point{20, 20, 0}
+	var b bytes.Buffer
+	if err := RenderSVG(&b, ros, Depots{}, m, 0); err == nil {
+		t.Errorf("no error for an end at %v", ros[0].End)
+	}
+	if err := RenderPNG(&b, ros, Depots{}, m, 1); err == nil {
+		t.Errorf("no error for floor 1 of 1")
+	}
+}
@@ -0,0 +1,172 @@
+package warehouse
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"time"
+)
+
+// Drift is a product of a saved plan that moved, to another shelf or level,
+// or disappeared since planning
+type Drift struct {
+	SKU          int
+	Planned      Point
+	PlannedLevel int
+	Now          Point
+	Level        int
+	Missing      bool
+}
+
+func (d Drift) String() string {
+	if d.Missing {
+		return fmt.Sprintf("item id %v planned at %v no longer exists", d.SKU, layout.SlotName(d.Planned, d.PlannedLevel))
+	}
+	return fmt.Sprintf("item id %v moved from %v to %v", d.SKU, layout.SlotName(d.Planned, d.PlannedLevel), layout.SlotName(d.Now, d.Level))
+}
+
+// TripEvaluation compares a trip of a saved plan with the current layout and products.
+// Length and Effort are measured now, without the products that no longer exist;
+// Length is in meters, like the planned one.
+type TripEvaluation struct {
+	Trip          int
+	Planned       PlanTotals
+	Length        float64
+	Effort        float64
+	MissingWeight bool
+	Drift         []Drift
+}
+
+// LoadPlan returns the plan saved at path. Besides the versioned plan schema it reads
+// the RouteOrder JSON written before it, a single RouteOrder or a list of them.
+func LoadPlan(path string) Plan {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		log.Fatal(err)
+	}
+	var probe struct {
+		Version *int `json:"version"`
+	}
+	data = bytes.TrimSpace(data)
+	if len(data) > 0 && data[0] == '{' {
+		if err := json.Unmarshal(data, &probe); err != nil {
+			log.Fatal(err)
+		}
+	}
+	if probe.Version != nil {
+		plan, err := DecodePlan(bytes.NewReader(data))
+		if err != nil {
+			log.Fatalf("Plan %v: %v", path, err)
+		}
+		return plan
+	}
+	var ros []RouteOrder
+	if len(data) > 0 && data[0] == '{' {
+		var ro RouteOrder
+		err = json.Unmarshal(data, &ro)
+		ros = append(ros, ro)
+	} else {
+		err = json.Unmarshal(data, &ros)
+	}
+	if err != nil {
+		log.Fatalf("Plan %v: %v", path, err)
+	}
+	return legacyPlan(ros)
+}
+
+// legacyPlan returns the plan of saved RouteOrders. The picks keep the positions
+// the products had when planned; the walks are not split by pick and are left out.
+func legacyPlan(ros []RouteOrder) Plan {
+	plan := Plan{Version: PlanVersion, Metadata: PlanMetadata{Algorithm: "legacy"}, Trips: []PlanTrip{}}
+	for b, ro := range ros {
+		for i, o := range ro.Orders {
+			start, end := ro.Start, ro.End
+			if i < len(ro.Starts) {
+				start, end = ro.Starts[i], ro.Ends[i]
+			}
+			trip := PlanTrip{Batch: b, Start: ToPlanPoint(start), End: ToPlanPoint(end)}
+			for j, item := range o {
+				step := PlanStep{Type: StepPick, SKU: item.ProdID, OrderID: item.OrderID}
+				if i < len(ro.Products) && j < len(ro.Products[i]) {
+					location := ToPlanPoint(ro.Products[i][j].Pos)
+					step.Location = &location
+					step.Level = ro.Products[i][j].Level
+				}
+				trip.Steps = append(trip.Steps, step)
+			}
+			trip.Totals = PlanTotals{Trips: 1, Items: len(o)}
+			if i < len(ro.Lengths) {
+				trip.Totals.Length = ro.Lengths[i]
+			}
+			plan.add(trip)
+		}
+	}
+	return plan
+}
+
+// currentOrder returns the picks of the trip whose products still exist
+// and the drift of the products since planning
+func currentOrder(trip PlanTrip, m map[int]Product) (Order, []Drift) {
+	var o Order
+	var drift []Drift
+	for _, step := range trip.Steps {
+		if step.Type != StepPick {
+			continue
+		}
+		var planned Point
+		if step.Location != nil {
+			planned = step.Location.Point()
+		}
+		prod, ok := m[step.SKU]
+		if !ok {
+			drift = append(drift, Drift{SKU: step.SKU, Planned: planned, PlannedLevel: step.Level, Missing: true})
+			continue
+		}
+		if step.Location != nil && (prod.Pos != planned || prod.Level != step.Level) {
+			drift = append(drift, Drift{SKU: step.SKU, Planned: planned, PlannedLevel: step.Level, Now: prod.Pos, Level: prod.Level})
+		}
+		o = append(o, Item{step.SKU, step.OrderID})
+	}
+	return o, drift
+}
+
+// EvaluatePlan returns every trip of the plan measured against the current
+// layout and products, its effort as in PlanTotals
+func EvaluatePlan(plan Plan, m map[int]Product, r *Router, pathInfo map[Point]map[Point]float64) []TripEvaluation {
+	var evals []TripEvaluation
+	for i, trip := range plan.Trips {
+		o, drift := currentOrder(trip, m)
+		eval := TripEvaluation{Trip: i, Planned: trip.Totals, Drift: drift}
+		if len(o) > 0 {
+			start, end := trip.Start.Point(), trip.End.Point()
+			eval.Length = RouteLength(o, start, end, m, r)
+			eval.Effort, eval.MissingWeight = RouteEffort(o, start, end, m, pathInfo)
+		}
+		evals = append(evals, eval)
+	}
+	return evals
+}
+
+// ReoptimizePlan returns the plan with every trip sequenced again by opt, keeping
+// its batch, its depots and its items that still exist, so plans of different
+// days or optimizers can be compared. costInfo is the cost opt minimizes.
+func ReoptimizePlan(plan Plan, m map[int]Product, r *Router, pathInfo, costInfo map[Point]map[Point]float64, opt Optimizer, meta PlanMetadata) Plan {
+	started := time.Now()
+	newPlan := Plan{Version: PlanVersion, Trips: []PlanTrip{}}
+	for _, trip := range plan.Trips {
+		o, _ := currentOrder(trip, m)
+		if len(o) == 0 {
+			continue
+		}
+		start, end := trip.Start.Point(), trip.End.Point()
+		t := NewPlanTrip(opt(o, start, end, m, costInfo), start, end, m, r, pathInfo)
+		t.Batch, t.Worker, t.Zone = trip.Batch, trip.Worker, trip.Zone
+		newPlan.add(t)
+	}
+	meta.Created = started
+	meta.Runtime = time.Since(started).Seconds()
+	newPlan.Metadata = meta
+	return newPlan
+}
@@ -0,0 +1,221 @@
+package warehouse
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"sort"
+)
+
+// OrderInfo is the time window and the priority of an order.
+// Times are in seconds from the start of the shift and a zero Due means no cut-off.
+// Orders with a higher Priority go first among orders due at the same time.
+type OrderInfo struct {
+	Release, Due float64
+	Priority     int
+}
+
+// Lateness is the time an order is finished under a plan
+type Lateness struct {
+	OrderID int     `json:"order_id"`
+	Due     float64 `json:"due,omitempty"`
+	Finish  float64 `json:"finish"`
+}
+
+// Late reports whether the order misses its cut-off
+func (l Lateness) Late() bool {
+	return l.Due > 0 && l.Finish > l.Due
+}
+
+func (l Lateness) String() string {
+	return fmt.Sprintf("order#%v due at %vs finished at %vs", l.OrderID, l.Due, l.Finish)
+}
+
+// ParseOrderSchedule returns the orders and their time windows from a file of
+// "id, release, due, priority, item[, item...]" lines
+func ParseOrderSchedule(path string) ([]Order, map[int]OrderInfo) {
+	records, ok := readConfig(path)
+	if !ok {
+		log.Fatalf("Order file %v not found.", path)
+	}
+	return scheduleOrders(records)
+}
+
+// scheduleOrders returns the orders and their time windows of schedule records,
+// the records of one id merged as in mergeOrders
+func scheduleOrders(records [][]string) ([]Order, map[int]OrderInfo) {
+	var rows []OrderRecord
+	for _, rec := range records {
+		if len(rec) < 5 {
+			log.Fatalf("Order %v has no items.", rec)
+		}
+		r := OrderRecord{OrderID: configInt(rec, 0), hasID: true, Release: configFloat(rec, 1), Due: configFloat(rec, 2), Priority: configInt(rec, 3)}
+		for i := 4; i < len(rec); i++ {
+			r.Items = append(r.Items, configInt(rec, i))
+		}
+		rows = append(rows, r)
+	}
+	return mergeOrders(rows)
+}
+
+// batchInfo returns the time window of the orders of o together:
+// the latest release, the earliest cut-off and the highest priority
+func batchInfo(o Order, info map[int]OrderInfo) OrderInfo {
+	var b OrderInfo
+	seen := make(map[int]bool)
+	for _, item := range o {
+		if seen[item.OrderID] {
+			continue
+		}
+		seen[item.OrderID] = true
+		oi := info[item.OrderID]
+		b.Release = math.Max(b.Release, oi.Release)
+		if oi.Due > 0 && (b.Due == 0 || oi.Due < b.Due) {
+			b.Due = oi.Due
+		}
+		if len(seen) == 1 || oi.Priority > b.Priority {
+			b.Priority = oi.Priority
+		}
+	}
+	return b
+}
+
+// urgent reports whether a time window goes before b: earliest cut-off first,
+// then highest priority, then earliest release
+func (a OrderInfo) urgent(b OrderInfo) bool {
+	da, db := a.Due, b.Due
+	if da == 0 {
+		da = math.Inf(1)
+	}
+	if db == 0 {
+		db = math.Inf(1)
+	}
+	if da != db {
+		return da < db
+	}
+	if a.Priority != b.Priority {
+		return a.Priority > b.Priority
+	}
+	return a.Release < b.Release
+}
+
+// ByDue returns a closure that orders the Orders by cut-off, then by priority
+func ByDue(info map[int]OrderInfo) By {
+	return func(o1, o2 *Order, m map[int]Product) bool {
+		return batchInfo(*o1, info).urgent(batchInfo(*o2, info))
+	}
+}
+
+// MergeOrdersByDue returns the reconbined orders that fit in the cart capacity c.
+// Orders are taken by cut-off and only join the batch of orders due next to them
+// when neither the order nor the batch has to wait for the release of the other.
+func MergeOrdersByDue(orders []Order, info map[int]OrderInfo, m map[int]Product, c Capacity) []Order {
+	var reOrders []Order
+	var load Capacity
+	By(ByDue(info)).Sort(orders, m)
+	for _, o := range orders {
+		ol := OrderLoad(o, m)
+		n := len(reOrders)
+		if n > 0 && c.Fits(load.Add(ol)) {
+			b := batchInfo(reOrders[n-1], info)
+			oi := info[o[0].OrderID]
+			if oi.Release <= b.Release && (oi.Due == 0 || b.Release < oi.Due) {
+				reOrders[n-1] = append(reOrders[n-1], o...)
+				load = load.Add(ol)
+				continue
+			}
+		}
+		reOrders = append(reOrders, append(Order{}, o...))
+		load = ol
+	}
+	return reOrders
+}
+
+// batchDuration returns the time in seconds to walk and pick all trips of the batch
+func batchDuration(ro RouteOrder, m map[int]Product, r *Router, pathInfo map[Point]map[Point]float64, cm CostModel) float64 {
+	var t float64
+	for i, o := range ro.Orders {
+		start, end := ro.Start, ro.End
+		if i < len(ro.Starts) {
+			start, end = ro.Starts[i], ro.Ends[i]
+		}
+		t += RouteDuration(o, start, end, m, r, pathInfo, cm)
+	}
+	return t
+}
+
+// SequenceBatches returns the batches in the order one picker works them and the
+// time every order is finished. Among the released batches the most urgent one
+// is picked next; when none is released, or working it would make a more urgent
+// batch released later miss its cut-off, the picker waits for the next release.
+// pathInfo must hold the travel costs built by BuildPathInfo with the Router.
+func SequenceBatches(ros []RouteOrder, info map[int]OrderInfo, m map[int]Product, r *Router, pathInfo map[Point]map[Point]float64, cm CostModel) ([]RouteOrder, []Lateness) {
+	windows := make([]OrderInfo, len(ros))
+	durations := make([]float64, len(ros))
+	for i, ro := range ros {
+		durations[i] = batchDuration(ro, m, r, pathInfo, cm)
+		var all Order
+		for _, o := range ro.Orders {
+			all = append(all, o...)
+		}
+		windows[i] = batchInfo(all, info)
+	}
+	done := make([]bool, len(ros))
+	finish := make(map[int]float64)
+	var sequence []RouteOrder
+	var clock float64
+	var pos Point
+	for len(sequence) < len(ros) {
+		next := -1
+		for i := range ros {
+			if done[i] || windows[i].Release > clock {
+				continue
+			}
+			if next < 0 || windows[i].urgent(windows[next]) {
+				next = i
+			}
+		}
+		for j := range ros {
+			if next < 0 || done[j] || windows[j].Release <= clock || windows[j].Due == 0 || !windows[j].urgent(windows[next]) {
+				continue
+			}
+			due, release := windows[j].Due, windows[j].Release
+			if release+durations[j] <= due && math.Max(clock+durations[next], release)+durations[j] > due {
+				next = -1
+			}
+		}
+		if next < 0 {
+			release := math.Inf(1)
+			for i := range ros {
+				if !done[i] && windows[i].Release > clock {
+					release = math.Min(release, windows[i].Release)
+				}
+			}
+			clock = release
+			continue
+		}
+		done[next] = true
+		ro := ros[next]
+		for i, o := range ro.Orders {
+			start, end := ro.Start, ro.End
+			if i < len(ro.Starts) {
+				start, end = ro.Starts[i], ro.Ends[i]
+			}
+			if len(sequence) > 0 || i > 0 {
+				clock += pathInfo[pos][start] / cm.WalkSpeed
+			}
+			clock += RouteDuration(o, start, end, m, r, pathInfo, cm)
+			for _, item := range o {
+				finish[item.OrderID] = clock
+			}
+			pos = end
+		}
+		sequence = append(sequence, ro)
+	}
+	var lateness []Lateness
+	for id, f := range finish {
+		lateness = append(lateness, Lateness{id, info[id].Due, f})
+	}
+	sort.Slice(lateness, func(i, j int) bool { return lateness[i].OrderID < lateness[j].OrderID })
+	return sequence, lateness
+}
@@ -0,0 +1,68 @@
+package warehouse
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSequenceBatches(t *testing.T) {
+	m := testProducts(t)
+	r := NewRouter(TravelModel{})
+	pathInfo := BuildPathInfo("", r)
+	cm := DefaultCostModel
+	// from the origin and back, picking product 0 takes 9 s, product 1 21 s and product 2 33 s
+	orders := map[int]Order{1: {{2, 1}}, 2: {{0, 2}}, 3: {{1, 3}}}
+	for _, tc := range []struct {
+		name     string
+		batches  []int
+		info     map[int]OrderInfo
+		want     []int
+		lateness []Lateness
+		late     []int
+	}{
+		// in the given sequence order 2 would be finished at 42 s, late
+		{"late order moved earlier", []int{1, 2, 3},
+			map[int]OrderInfo{1: {Due: 100}, 2: {Due: 20}},
+			[]int{2, 1, 3}, []Lateness{{1, 100, 42}, {2, 20, 9}, {3, 0, 63}}, nil},
+		{"priority", []int{1, 2, 3},
+			map[int]OrderInfo{1: {Priority: 1}, 3: {Priority: 2}},
+			[]int{3, 1, 2}, []Lateness{{1, 0, 54}, {2, 0, 63}, {3, 0, 21}}, nil},
+		// order 1 would make order 2, released at 10 s, miss its cut-off
+		{"wait for an urgent release", []int{1, 2},
+			map[int]OrderInfo{2: {Release: 10, Due: 25}},
+			[]int{2, 1}, []Lateness{{1, 0, 52}, {2, 25, 19}}, nil},
+		{"work while waiting", []int{2, 3},
+			map[int]OrderInfo{2: {Release: 30, Due: 60}},
+			[]int{3, 2}, []Lateness{{2, 60, 39}, {3, 0, 21}}, nil},
+		{"late anyway", []int{1},
+			map[int]OrderInfo{1: {Due: 10}},
+			[]int{1}, []Lateness{{1, 10, 33}}, []int{1}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var ros []RouteOrder
+			for _, id := range tc.batches {
+				ros = append(ros, Orders2Routes([]Order{orders[id]}, Point{}, Point{}, m, r))
+			}
+			sequence, lateness := SequenceBatches(ros, tc.info, m, r, pathInfo, cm)
+			var got []int
+			for _, ro := range sequence {
+				got = append(got, ro.Orders[0][0].OrderID)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("sequence %v, want %v", got, tc.want)
+			}
+			if !reflect.DeepEqual(lateness, tc.lateness) {
+				t.Errorf("lateness %v, want %v", lateness, tc.lateness)
+			}
+			var late []int
+			for _, l := range lateness {
+				if l.Late() {
+					late = append(late, l.OrderID)
+				}
+			}
+			if !reflect.DeepEqual(late, tc.late) {
+				t.Errorf("orders %v late, want %v", late, tc.late)
+			}
+		})
+	}
+}
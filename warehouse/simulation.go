@@ -0,0 +1,301 @@
+package warehouse
+
+import (
+	"container/heap"
+	"log"
+	"math"
+)
+
+// SimConfig defines the parameters of a congestion simulation.
+// The pickers walk and pick as timed by the CostModel, so that a picker
+// never held up takes as long as the RouteDuration of its trips.
+// AisleCapacity is the number of carts allowed in one aisle segment (0 for no limit).
+// Lifts and stairs carry as many pickers as the Capacity of their Connector.
+type SimConfig struct {
+	CostModel
+	AisleCapacity int
+}
+
+// DefaultSimConfig is used when no simulation config is given
+var DefaultSimConfig = SimConfig{CostModel: DefaultCostModel, AisleCapacity: 1}
+
+// PickerStats is the outcome of one picker in a simulation
+type PickerStats struct {
+	Picker     int
+	Completion float64
+	Waiting    float64
+	Blocked    int
+	Deadlocks  int
+}
+
+// LoadSimConfig returns the simulation parameters of the cost model cm
+// from a config file of "aisle_capacity, value" lines
+func LoadSimConfig(path string, cm CostModel) SimConfig {
+	cfg := DefaultSimConfig
+	cfg.CostModel = cm
+	records, ok := readConfig(path)
+	if !ok {
+		return cfg
+	}
+	for _, rec := range records {
+		switch rec[0] {
+		case "speed", "pick_time":
+			log.Fatal("Walk speed and pick times are set in the cost model config.")
+		case "aisle_capacity":
+			cfg.AisleCapacity = configInt(rec, 1)
+		default:
+			log.Fatalf("Unknown simulation entry %q.", rec[0])
+		}
+	}
+	return cfg
+}
+
+// simStep is either a move into cell, or a pick at cell taking dur seconds
+// when the picker is already there
+type simStep struct {
+	cell Point
+	pick bool
+	dur  float64
+}
+
+// expandPath returns the unit steps of a path of turning points, without its first point
+func expandPath(path Path) []Point {
+	var cells []Point
+	if len(path) < 2 {
+		return cells
+	}
+	for i := range path[1:] {
+		p, q := path[i], path[i+1]
+		for p != q {
+			switch {
+			case p.X < q.X:
+				p.X++
+			case p.X > q.X:
+				p.X--
+			case p.Y < q.Y:
+				p.Y++
+			case p.Y > q.Y:
+				p.Y--
+			case p.F < q.F:
+				p.F++
+			default:
+				p.F--
+			}
+			cells = append(cells, p)
+		}
+	}
+	return cells
+}
+
+// simSteps returns the steps of a picker walking all the trips of a RouteOrder.
+// A pick takes its time by the cost model, reaching its level included.
+func simSteps(ro RouteOrder, m map[int]Product, r *Router, cm CostModel) []simStep {
+	var steps []simStep
+	walk := func(src, dest Point) {
+		for _, c := range expandPath(r.FindPath(src, dest)) {
+			steps = append(steps, simStep{cell: c})
+		}
+	}
+	pos := ro.Start
+	for i, order := range ro.Orders {
+		start, end := ro.Start, ro.End
+		if i < len(ro.Starts) {
+			start, end = ro.Starts[i], ro.Ends[i]
+		}
+		if pos != start {
+			walk(pos, start)
+			pos = start
+		}
+		for j, item := range order {
+			prod := m[item.ProdID]
+			dest := FindDest(pos, prod)
+			walk(pos, dest)
+			dur := cm.HandlingTime + cm.ReachTime*prod.h + reachCost(prod)/cm.WalkSpeed
+			if j == 0 || dest != pos {
+				dur += cm.PickTime
+			}
+			steps = append(steps, simStep{cell: dest, pick: true, dur: dur})
+			pos = dest
+		}
+		walk(pos, end)
+		pos = end
+	}
+	return steps
+}
+
+// isAisleSegment reports whether the cell is a narrow aisle segment next to shelves
+func isAisleSegment(p Point) bool {
+	return p.X%2 == 0 && p.Y%2 == 1
+}
+
+// simResource returns the resource a picker holds in the cell and its capacity, 0 for no limit.
+// The cells of a connector on all floors are one resource.
+func simResource(p Point, cfg SimConfig) (Point, int) {
+	for _, c := range layout.Connectors {
+		if c.X == p.X && c.Y == p.Y && c.Serves(p.F) {
+			return Point{X: c.X, Y: c.Y, F: -1}, c.Capacity
+		}
+	}
+	if isAisleSegment(p) {
+		return p, cfg.AisleCapacity
+	}
+	return p, 0
+}
+
+type simEvent struct {
+	time   float64
+	seq    int
+	picker int
+}
+
+type simQueue []simEvent
+
+func (q simQueue) Len() int { return len(q) }
+
+func (q simQueue) Less(i, j int) bool {
+	if q[i].time == q[j].time {
+		return q[i].seq < q[j].seq
+	}
+	return q[i].time < q[j].time
+}
+
+func (q simQueue) Swap(i, j int) { q[i], q[j] = q[j], q[i] }
+
+func (q *simQueue) Push(x interface{}) { *q = append(*q, x.(simEvent)) }
+
+func (q *simQueue) Pop() interface{} {
+	old := *q
+	n := len(old)
+	e := old[n-1]
+	*q = old[0 : n-1]
+	return e
+}
+
+// Simulate replays the plans of several pickers on the grid at the same time.
+// A picker waits in place when the next aisle segment is full.
+// When every remaining picker waits for another, the one waiting longest
+// squeezes through and a deadlock is counted for it.
+func Simulate(plans []RouteOrder, m map[int]Product, r *Router, cfg SimConfig) []PickerStats {
+	n := len(plans)
+	stats := make([]PickerStats, n)
+	steps := make([][]simStep, n)
+	next := make([]int, n)
+	pos := make([]Point, n)
+	waiting := make([]bool, n)
+	waitStart := make([]float64, n)
+	occupancy := make(map[Point]int)
+	waiters := make(map[Point][]int)
+
+	var q simQueue
+	seq := 0
+	schedule := func(t float64, picker int) {
+		heap.Push(&q, simEvent{t, seq, picker})
+		seq++
+	}
+	release := func(t float64, p Point) {
+		r, capacity := simResource(p, cfg)
+		if capacity <= 0 {
+			return
+		}
+		occupancy[r]--
+		for _, w := range waiters[r] {
+			schedule(t, w)
+		}
+		delete(waiters, r)
+	}
+	occupy := func(p Point) {
+		if r, capacity := simResource(p, cfg); capacity > 0 {
+			occupancy[r]++
+		}
+	}
+	// enter moves the picker into its next cell and schedules its arrival
+	enter := func(t float64, picker int) {
+		step := steps[picker][next[picker]]
+		if waiting[picker] {
+			waiting[picker] = false
+			stats[picker].Waiting += t - waitStart[picker]
+		}
+		release(t, pos[picker])
+		occupy(step.cell)
+		dt := r.PathCost(Path{pos[picker], step.cell}) / cfg.WalkSpeed
+		pos[picker] = step.cell
+		next[picker]++
+		schedule(t+dt, picker)
+	}
+
+	for i, ro := range plans {
+		stats[i].Picker = i
+		steps[i] = simSteps(ro, m, r, cfg.CostModel)
+		pos[i] = ro.Start
+		occupy(ro.Start)
+		schedule(0, i)
+	}
+	var now float64
+	done := 0
+	for done < n {
+		if q.Len() == 0 {
+			// deadlock: every remaining picker waits for a segment held by another
+			picker := -1
+			for i := range waiting {
+				if waiting[i] && (picker < 0 || waitStart[i] < waitStart[picker]) {
+					picker = i
+				}
+			}
+			r, _ := simResource(steps[picker][next[picker]].cell, cfg)
+			for k, w := range waiters[r] {
+				if w == picker {
+					waiters[r] = append(waiters[r][:k], waiters[r][k+1:]...)
+					break
+				}
+			}
+			stats[picker].Deadlocks++
+			enter(now, picker)
+			continue
+		}
+		e := heap.Pop(&q).(simEvent)
+		now = e.time
+		p := e.picker
+		if next[p] == len(steps[p]) {
+			release(now, pos[p])
+			stats[p].Completion = now
+			done++
+			continue
+		}
+		step := steps[p][next[p]]
+		if step.pick && step.cell == pos[p] {
+			next[p]++
+			schedule(now+step.dur, p)
+			continue
+		}
+		r, capacity := simResource(step.cell, cfg)
+		if cur, _ := simResource(pos[p], cfg); capacity > 0 && r != cur && occupancy[r] >= capacity {
+			if !waiting[p] {
+				waiting[p] = true
+				waitStart[p] = now
+				stats[p].Blocked++
+			}
+			waiters[r] = append(waiters[r], p)
+			continue
+		}
+		enter(now, p)
+	}
+	return stats
+}
+
+// TotalWaiting returns the total waiting time of all pickers
+func TotalWaiting(stats []PickerStats) float64 {
+	var total float64
+	for _, s := range stats {
+		total += s.Waiting
+	}
+	return total
+}
+
+// Makespan returns the latest completion time of all pickers
+func Makespan(stats []PickerStats) float64 {
+	var max float64
+	for _, s := range stats {
+		max = math.Max(max, s.Completion)
+	}
+	return max
+}
@@ -0,0 +1,74 @@
+package warehouse
+
+import (
+	"math"
+	"testing"
+)
+
+func TestSimulateMatchesRouteDuration(t *testing.T) {
+	l := DefaultLayout
+	l.MaxX, l.MaxY = 8, 8
+	l.LevelCost = 2
+	SetLayout(l)
+	t.Cleanup(func() { SetLayout(DefaultLayout) })
+	// products 1 and 2 face each other across the aisle at x = 4, product 3 is on level 2
+	m := ParseProductInfo(writeInput(t, "grid.csv", "0, 0, 0\n1, 1, 2\n2, 2, 2\n3, 3, 1, 2\n"), nil)
+	r := NewRouter(TravelModel{})
+	pathInfo := BuildPathInfo("", r)
+	cm := CostModel{WalkSpeed: 2, PickTime: 5, HandlingTime: 1}
+	start, end := Point{0, 0, 0}, Point{8, 8, 0}
+	orders := []Order{{{0, 1}, {1, 1}, {2, 1}, {3, 1}}, {{3, 2}, {0, 2}}}
+	ro := Orders2Routes(orders, start, end, m, r)
+
+	stats := Simulate([]RouteOrder{ro}, m, r, SimConfig{CostModel: cm})
+	// a picker alone walks from the end of a trip back to the start of the next
+	want := RouteDuration(orders[0], start, end, m, r, pathInfo, cm) +
+		pathInfo[end][start]/cm.WalkSpeed +
+		RouteDuration(orders[1], start, end, m, r, pathInfo, cm)
+	if got := stats[0].Completion; math.Abs(got-want) > 1e-9 {
+		t.Errorf("completion %v, want %v as planned", got, want)
+	}
+	if stats[0].Waiting != 0 || stats[0].Blocked != 0 {
+		t.Errorf("a picker alone waited %v s, blocked %v time(s)", stats[0].Waiting, stats[0].Blocked)
+	}
+}
+
+func TestSimulateAisleCapacity(t *testing.T) {
+	m := testProducts(t)
+	r := NewRouter(TravelModel{})
+	cm := CostModel{WalkSpeed: 1, PickTime: 5, HandlingTime: 2}
+	// both pickers pick product 0 from the aisle segment next to the start:
+	// 1 s there, 7 s picking and 1 s back
+	start := Point{0, 0, 0}
+	ro := Orders2Routes([]Order{{{0, 1}}}, start, start, m, r)
+	for _, tc := range []struct {
+		name       string
+		capacity   int
+		completion []float64
+		waiting    []float64
+		blocked    []int
+	}{
+		{"no limit", 0, []float64{9, 9}, []float64{0, 0}, []int{0, 0}},
+		{"one cart", 1, []float64{9, 17}, []float64{0, 8}, []int{0, 1}},
+		{"two carts", 2, []float64{9, 9}, []float64{0, 0}, []int{0, 0}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			stats := Simulate([]RouteOrder{ro, ro}, m, r, SimConfig{CostModel: cm, AisleCapacity: tc.capacity})
+			for i, st := range stats {
+				if st.Completion != tc.completion[i] || st.Waiting != tc.waiting[i] || st.Blocked != tc.blocked[i] {
+					t.Errorf("picker %v: done after %v s, waited %v s, blocked %v time(s), want %v s, %v s, %v",
+						i, st.Completion, st.Waiting, st.Blocked, tc.completion[i], tc.waiting[i], tc.blocked[i])
+				}
+				if st.Deadlocks != 0 {
+					t.Errorf("picker %v: %v deadlock(s)", i, st.Deadlocks)
+				}
+			}
+			if got, want := Makespan(stats), tc.completion[1]; got != want {
+				t.Errorf("makespan %v, want %v", got, want)
+			}
+			if got, want := TotalWaiting(stats), tc.waiting[1]; got != want {
+				t.Errorf("total waiting %v, want %v", got, want)
+			}
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package warehouse
 
 import (
+	"math"
 	"sort"
 )
 
@@ -44,32 +45,88 @@ func ByItemWeightReverse(i1, i2 *Item, m map[int]Product) bool {
 	return m[i1.ProdID].w > m[i2.ProdID].w
 }
 
-// SplitOrder splits the order that has total weight larger than max
-func SplitOrder(order Order, m map[int]Product, max float64) []Order {
-	if OrderWeight(order, m) <= max {
+// SplitOrder splits the order that does not fit in the cart capacity c.
+// Every split takes one compartment of the cart per OrderID in it.
+func SplitOrder(order Order, m map[int]Product, c Capacity) []Order {
+	if c.Fits(OrderLoad(order, m)) {
 		return []Order{order}
 	}
 	reOrders := make([]Order, 1)
-	ordersWeight := []float64{0.0}
 	ByI(ByItemWeightReverse).Sort(order, m)
 	var fit bool
 	for _, i := range order {
 		fit = false
 		for j := range reOrders {
-			iw := m[i.ProdID].w
-			if (ordersWeight[j]+iw <= max && len(reOrders[j]) < maxItem) || len(reOrders[j]) == 0 {
-				reOrders[j] = append(reOrders[j], i)
-				ordersWeight[j] += iw
+			trip := append(append(Order{}, reOrders[j]...), i)
+			if c.Fits(OrderLoad(trip, m)) || len(reOrders[j]) == 0 {
+				reOrders[j] = trip
 				fit = true
 				break
 			}
 		}
 		if !fit {
-			var newOrder Order
-			newOrder = append(newOrder, i)
-			reOrders = append(reOrders, newOrder)
-			ordersWeight = append(ordersWeight, m[i.ProdID].w)
+			reOrders = append(reOrders, Order{i})
 		}
 	}
 	return reOrders
-}
\ No newline at end of file
+}
+
+// SplitOrderByRoute splits the order that does not fit in the cart capacity c
+// into geographically compact trips (route-first, cluster-second):
+// the items are sequenced into one giant tour first, then the tour is cut
+// into consecutive trips so that the total length of all trips is minimal.
+func SplitOrderByRoute(order Order, start, end Point, m map[int]Product, pathInfo map[Point]map[Point]float64, c Capacity) []Order {
+	if c.Fits(OrderLoad(order, m)) {
+		return []Order{order}
+	}
+	tour := NNIOrderOptimizer(order, start, end, m, pathInfo)
+	n := len(tour)
+	// cost[j] is the min total length of the trips covering tour[:j],
+	// the last of which starts at tour[prev[j]]
+	cost := make([]float64, n+1)
+	prev := make([]int, n+1)
+	for j := range cost[1:] {
+		cost[j+1] = math.Inf(1)
+	}
+	for i := 0; i < n; i++ {
+		for j := i; j < n; j++ {
+			if j > i && !c.Fits(OrderLoad(tour[i:j+1], m)) {
+				break
+			}
+			length := cost[i] + RouteCost(tour[i:j+1], start, end, m, pathInfo)
+			if length < cost[j+1] {
+				cost[j+1] = length
+				prev[j+1] = i
+			}
+		}
+	}
+	var reOrders []Order
+	for j := n; j > 0; j = prev[j] {
+		trip := make(Order, j-prev[j])
+		copy(trip, tour[prev[j]:j])
+		reOrders = append([]Order{trip}, reOrders...)
+	}
+	return reOrders
+}
+
+// PlanBatches returns the routes of the merged orders: every batch is split into
+// trips by route length and every trip is optimized by opt between the depots.
+// It fails when a trip cannot be walked between the depots.
+func PlanBatches(batches []Order, start, end Point, d Depots, m map[int]Product, r *Router, pathInfo map[Point]map[Point]float64, c Capacity, opt Optimizer) ([]RouteOrder, error) {
+	var ros []RouteOrder
+	for _, batch := range batches {
+		var ods []Order
+		var starts, ends []Point
+		for _, order := range SplitOrderByRoute(batch, start, end, m, pathInfo, c) {
+			result, s, e, err := DepotOrderOptimizer(order, d, m, pathInfo, opt)
+			if err != nil {
+				return nil, err
+			}
+			ods = append(ods, result)
+			starts = append(starts, s)
+			ends = append(ends, e)
+		}
+		ros = append(ros, Orders2RoutesDepots(ods, starts, ends, m, r))
+	}
+	return ros, nil
+}
@@ -0,0 +1,26 @@
+package warehouse
+
+import "testing"
+
+func TestSplitOrderCompartments(t *testing.T) {
+	m := testProducts(t)
+	pathInfo := BuildPathInfo("", NewRouter(TravelModel{}))
+	// a batch of three orders for a cart of two compartments
+	batch := Order{{0, 1}, {1, 2}, {2, 3}, {0, 2}}
+	c := Capacity{Compartments: 2}
+	for name, trips := range map[string][]Order{
+		"by route":  SplitOrderByRoute(batch, Point{0, 0, 0}, Point{0, 0, 0}, m, pathInfo, c),
+		"by weight": SplitOrder(append(Order{}, batch...), m, c),
+	} {
+		items := 0
+		for _, trip := range trips {
+			if load := OrderLoad(trip, m); !c.Fits(load) {
+				t.Errorf("%v: trip %v takes %v compartments", name, trip, load.Compartments)
+			}
+			items += len(trip)
+		}
+		if items != len(batch) || len(trips) < 2 {
+			t.Errorf("%v: trips %v, want the %v items in 2 trips or more", name, trips, len(batch))
+		}
+	}
+}
@@ -0,0 +1,339 @@
+package warehouse
+
+import (
+	"container/heap"
+	"log"
+	"math"
+	"strings"
+	"sync"
+)
+
+// Direction is the direction of a move on the grid
+type Direction int
+
+const (
+	// Left decreases X
+	Left Direction = iota
+	// Right increases X
+	Right
+	// Down decreases Y
+	Down
+	// Up increases Y
+	Up
+	noDirection
+)
+
+var directionSteps = [...]Point{Left: {X: -1}, Right: {X: 1}, Down: {Y: -1}, Up: {Y: 1}}
+
+// TravelModel defines the cost of moving on the grid.
+// TurnPenalty is added to the length of a path per turn, in meters; it is the
+// only charge for turns, the optimizers and the durations take it from the path info.
+// Factors scale the length of moves by their direction, AisleFactors
+// override them for the vertical moves in the aisle at a given X
+// (e.g. a ramp), and an infinite factor forbids the direction (one-way aisle).
+type TravelModel struct {
+	TurnPenalty  float64
+	Factors      map[Direction]float64
+	AisleFactors map[int]map[Direction]float64
+}
+
+// LoadTravelModel returns the travel model from a config file of
+// "turn_penalty, value", "factor, direction, value" and
+// "aisle, x, direction, value" lines, direction being left|right|down|up
+func LoadTravelModel(path string) TravelModel {
+	var tm TravelModel
+	records, ok := readConfig(path)
+	if !ok {
+		return tm
+	}
+	for _, rec := range records {
+		switch rec[0] {
+		case "turn_penalty":
+			tm.TurnPenalty = configFloat(rec, 1)
+		case "factor":
+			if tm.Factors == nil {
+				tm.Factors = make(map[Direction]float64)
+			}
+			tm.Factors[parseDirection(rec, 1)] = configFloat(rec, 2)
+		case "aisle":
+			if tm.AisleFactors == nil {
+				tm.AisleFactors = make(map[int]map[Direction]float64)
+			}
+			x := configInt(rec, 1)
+			if tm.AisleFactors[x] == nil {
+				tm.AisleFactors[x] = make(map[Direction]float64)
+			}
+			tm.AisleFactors[x][parseDirection(rec, 2)] = configFloat(rec, 3)
+		default:
+			log.Fatalf("Unknown travel entry %q.", rec[0])
+		}
+	}
+	return tm
+}
+
+func parseDirection(rec []string, i int) Direction {
+	if i >= len(rec) {
+		log.Fatalf("Config entry %v is missing field #%v.", rec, i)
+	}
+	for d, name := range []string{"left", "right", "down", "up"} {
+		if strings.ToLower(rec[i]) == name {
+			return Direction(d)
+		}
+	}
+	log.Fatalf("Unknown direction %q.", rec[i])
+	return noDirection
+}
+
+// searched reports whether paths have to be searched, as with turn penalties
+// or direction factors the geometric paths may not be the cheapest or even allowed
+func (tm TravelModel) searched() bool {
+	return tm.TurnPenalty != 0 || len(tm.Factors) > 0 || len(tm.AisleFactors) > 0
+}
+
+// directional reports whether any move has a factor
+func (tm TravelModel) directional() bool {
+	return len(tm.Factors) > 0 || len(tm.AisleFactors) > 0
+}
+
+// factor returns the cost factor of moving in direction d from src
+func (tm TravelModel) factor(src Point, d Direction) float64 {
+	if d == Up || d == Down {
+		if f, ok := tm.AisleFactors[src.X][d]; ok {
+			return f
+		}
+	}
+	if f, ok := tm.Factors[d]; ok {
+		return f
+	}
+	return 1.0
+}
+
+// direction returns the direction of the straight segment from src to dest
+func direction(src, dest Point) Direction {
+	switch {
+	case dest.X < src.X:
+		return Left
+	case dest.X > src.X:
+		return Right
+	case dest.Y < src.Y:
+		return Down
+	case dest.Y > src.Y:
+		return Up
+	}
+	return noDirection
+}
+
+// PathCost returns the travel cost of the path: its length scaled by the
+// direction factors, plus the turn penalties
+func (tm TravelModel) PathCost(path Path) float64 {
+	cost := float64(countTurns(path)) * tm.TurnPenalty
+	if len(path) < 2 {
+		return cost
+	}
+	for i := range path[1:] {
+		if path[i].F != path[i+1].F {
+			cost += rideCost(path[i], path[i+1])
+			continue
+		}
+		length := PathLength(path[i : i+2])
+		if !tm.directional() {
+			cost += length
+			continue
+		}
+		// factors may differ cell by cell, so walk the segment in unit steps
+		src := path[i]
+		d := direction(src, path[i+1])
+		for _, dest := range expandPath(path[i : i+2]) {
+			cost += PathLength(Path{src, dest}) * tm.factor(src, d)
+			src = dest
+		}
+	}
+	return cost
+}
+
+// walkable reports whether the point is on the grid and not a shelf
+func walkable(p Point) bool {
+	return layout.Contains(p) && p.X*p.Y%2 == 0
+}
+
+// walkablePoints returns the walkable points of all floors
+func walkablePoints() []Point {
+	var points []Point
+	for f := 0; f < layout.Floors; f++ {
+		for i := 0; i <= layout.MaxX; i++ {
+			for j := 0; j <= layout.MaxY; j++ {
+				if i*j%2 == 0 {
+					points = append(points, Point{i, j, f})
+				}
+			}
+		}
+	}
+	return points
+}
+
+type travelState struct {
+	p Point
+	d Direction
+}
+
+type travelItem struct {
+	state travelState
+	cost  float64
+}
+
+type travelQueue []travelItem
+
+func (q travelQueue) Len() int            { return len(q) }
+func (q travelQueue) Less(i, j int) bool  { return q[i].cost < q[j].cost }
+func (q travelQueue) Swap(i, j int)       { q[i], q[j] = q[j], q[i] }
+func (q *travelQueue) Push(x interface{}) { *q = append(*q, x.(travelItem)) }
+
+func (q *travelQueue) Pop() interface{} {
+	old := *q
+	n := len(old)
+	item := old[n-1]
+	*q = old[0 : n-1]
+	return item
+}
+
+// travelTree holds the cheapest moves from a source under the TravelModel.
+// States remember the direction of the last move to charge turns.
+type travelTree struct {
+	src  Point
+	cost map[travelState]float64
+	prev map[travelState]travelState
+}
+
+// search runs Dijkstra from src over the grid
+func (tm TravelModel) search(src Point) *travelTree {
+	t := &travelTree{
+		src:  src,
+		cost: make(map[travelState]float64),
+		prev: make(map[travelState]travelState),
+	}
+	first := travelState{src, noDirection}
+	t.cost[first] = 0
+	q := travelQueue{{first, 0}}
+	for q.Len() > 0 {
+		item := heap.Pop(&q).(travelItem)
+		if item.cost > t.cost[item.state] {
+			continue
+		}
+		for d, step := range directionSteps {
+			dest := Point{item.state.p.X + step.X, item.state.p.Y + step.Y, item.state.p.F}
+			f := tm.factor(item.state.p, Direction(d))
+			if !walkable(dest) || math.IsInf(f, 1) {
+				continue
+			}
+			cost := item.cost + PathLength(Path{item.state.p, dest})*f
+			if item.state.d != noDirection && item.state.d != Direction(d) {
+				cost += tm.TurnPenalty
+			}
+			next := travelState{dest, Direction(d)}
+			if c, ok := t.cost[next]; !ok || cost < c {
+				t.cost[next] = cost
+				t.prev[next] = item.state
+				heap.Push(&q, travelItem{next, cost})
+			}
+		}
+	}
+	return t
+}
+
+// best returns the cheapest state reaching dest
+func (t *travelTree) best(dest Point) (travelState, bool) {
+	var best travelState
+	found := false
+	for d := Left; d <= noDirection; d++ {
+		s := travelState{dest, d}
+		if c, ok := t.cost[s]; ok && (!found || c < t.cost[best]) {
+			best = s
+			found = true
+		}
+	}
+	return best, found
+}
+
+// costTo returns the travel cost from the source to dest, +Inf if unreachable
+func (t *travelTree) costTo(dest Point) float64 {
+	s, ok := t.best(dest)
+	if !ok {
+		return math.Inf(1)
+	}
+	return t.cost[s]
+}
+
+// pathTo returns the turning points of the cheapest path from the source to dest
+func (t *travelTree) pathTo(dest Point) Path {
+	s, ok := t.best(dest)
+	if !ok || dest == t.src {
+		return nil
+	}
+	path := Path{dest}
+	for s.p != t.src {
+		prev := t.prev[s]
+		if prev.d != s.d && prev.p != t.src {
+			path = append(Path{prev.p}, path...)
+		}
+		s = prev
+	}
+	return append(Path{t.src}, path...)
+}
+
+// maxTrees is the number of search trees a Router keeps
+const maxTrees = 1024
+
+// Router finds the paths on the grid under a TravelModel. With turn penalties or
+// direction factors the cheapest allowed path is searched, and the search tree of
+// every source is kept for the next paths from it. A Router is safe for concurrent use.
+type Router struct {
+	Model TravelModel
+	mu    sync.Mutex
+	trees map[Point]*treeOnce
+}
+
+// treeOnce is the search tree from a source, searched once by the first path asking for it
+type treeOnce struct {
+	once sync.Once
+	tree *travelTree
+}
+
+// NewRouter returns a Router of the TravelModel
+func NewRouter(tm TravelModel) *Router {
+	return &Router{Model: tm}
+}
+
+// FindPath returns the turning points of the path from src to dest including both.
+// Paths to another floor go through a connector and change F at it.
+func (r *Router) FindPath(src, dest Point) Path {
+	if src.F != dest.F {
+		return r.findPathFloors(src, dest)
+	}
+	if !r.Model.searched() {
+		return FindPath(src, dest)
+	}
+	return r.tree(src).pathTo(dest)
+}
+
+// PathCost returns the travel cost of the path under the model of the Router
+func (r *Router) PathCost(path Path) float64 {
+	return r.Model.PathCost(path)
+}
+
+// tree returns the search tree from src, searching the grid the first time.
+// The lock is held only to find the tree, so searches from different sources
+// run at the same time while the paths from one source wait for its search.
+func (r *Router) tree(src Point) *travelTree {
+	r.mu.Lock()
+	t, ok := r.trees[src]
+	if !ok {
+		if r.trees == nil || len(r.trees) >= maxTrees {
+			r.trees = make(map[Point]*treeOnce)
+		}
+		t = &treeOnce{}
+		r.trees[src] = t
+	}
+	r.mu.Unlock()
+	t.once.Do(func() { t.tree = r.Model.search(src) })
+	return t.tree
+}
@@ -0,0 +1,39 @@
+package warehouse
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func TestRouterConcurrentPaths(t *testing.T) {
+	l := DefaultLayout
+	l.MaxX, l.MaxY = 8, 8
+	SetLayout(l)
+	t.Cleanup(func() { SetLayout(DefaultLayout) })
+	tm := TravelModel{TurnPenalty: 2}
+	sources := []Point{{0, 0, 0}, {4, 3, 0}, {8, 8, 0}}
+	dest := Point{6, 1, 0}
+	want := make([]Path, len(sources))
+	for i, src := range sources {
+		want[i] = NewRouter(tm).FindPath(src, dest)
+	}
+
+	// every source is searched by several paths at once on a shared Router
+	r := NewRouter(tm)
+	got := make([]Path, 4*len(sources))
+	var wg sync.WaitGroup
+	for i := range got {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			got[i] = r.FindPath(sources[i%len(sources)], dest)
+		}(i)
+	}
+	wg.Wait()
+	for i, path := range got {
+		if w := want[i%len(sources)]; !reflect.DeepEqual(path, w) {
+			t.Errorf("path from %v is %v, want %v", sources[i%len(sources)], path, w)
+		}
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"bufio"
 	"encoding/csv"
 	"fmt"
+	"io/ioutil"
 	"log"
 	"math"
 	"os"
@@ -15,11 +16,7 @@ import (
 )
 
 const (
-	gridPath    = "warehouse-grid.csv"
-	shelfLength = 1.0
-	shelfWidth  = 1.0
-	pathWidthX  = 1.0
-	pathWidthY  = 1.0
+	gridPath = "warehouse-grid.csv"
 )
 
 // Product defines the information of a product
@@ -28,11 +25,15 @@ type Product struct {
 	Pos        Point
 	wAvail     bool
 	w          float64
+	v          float64
+	h          float64
+	Level      int
 	l, r, u, d bool
 	pseudo     bool
 	pseudoIn   Point
 	pseudoOut  Point
 	OrderID	int
+	Class      string
 	//num int
 }
 
@@ -42,9 +43,10 @@ type Item struct {
 	OrderID	int
 }
 
-// Point defines the location of a point
+// Point defines the location of a point on floor F
 type Point struct {
 	X, Y int
+	F    int
 }
 
 // Path is a slice of Points
@@ -82,113 +84,170 @@ func posAssigner(prod *Product) *Product {
 }
 
 // ParseProductInfo returns a map that includes product info
+// from records of "id, x, y[, level[, floor]]" or "id, location"; level 0 is the ground.
+// The file may also have a header naming its columns or be JSON, see loadProducts.
 // TO-DO: ALSO FIND MAX/MIN INFO
 // MAYBE NOT NECESSARY?
 func ParseProductInfo(path string, dim map[int][]float64) map[int]Product {
-	records, err := ReadCSV(path)
-	if err != nil {
-		log.Fatal(err)
-	}
+	prods, own := loadProducts(path)
 	var m map[int]Product
 	m = make(map[int]Product)
-	for _, s := range records {
-		var temp [3]int
-		var err error
-		for i := range temp {
-			s[i] = strings.TrimSpace(s[i])
-			switch i {
-			case 0:
-				temp[i], err = strconv.Atoi(s[i])
-			default:
-				temp[i], err = strconv.Atoi(strings.Split(s[i], ".")[0])
-			}
-			if err != nil {
-				log.Fatal(err)
-			}
+	for _, prod := range prods {
+		d, ok := own[prod.id]
+		if !ok {
+			d, ok = dim[prod.id]
 		}
-		temp[1], temp[2] = coordinateConverter(temp[1], temp[2])
-		prod := Product{id: temp[0], Pos: Point{temp[1], temp[2]}, pseudo: false}
-		d, ok := dim[temp[0]]
 		if ok {
-			prod.wAvail = true
-			prod.w = d[3]
+			prod.wAvail = !math.IsNaN(d[3])
+			if prod.wAvail {
+				prod.w = d[3]
+			}
+			prod.v = d[0] * d[1] * d[2]
+			prod.h = d[2]
 		}
-		m[temp[0]] = *posAssigner(&prod)
+		m[prod.id] = *posAssigner(&prod)
 	}
 	return m
 }
 
-// BuildPathInfo return a nested map that records the distances between Points
-func BuildPathInfo(path string) map[Point]map[Point]float64 {
-	var m map[Point]map[Point]float64
-	m = make(map[Point]map[Point]float64)
-	for i := 0; i <= 38; i++ {
-		for j := 0; j <= 22; j++ {
-			if i*j%2 == 0 {
-				src := Point{i, j}
-				//srcstr := fmt.Sprintf("(%v %v)", i, j)
-				var m2 map[Point]float64
-				m2 = make(map[Point]float64)
-				for p := 0; p <= 38; p++ {
-					for q := 0; q <= 22; q++ {
-						if p*q%2 == 0 {
-							dest := Point{p, q}
-							//deststr := fmt.Sprintf("(%v %v)", p, q)
-							m2[dest] = PathLength(FindPath(src, dest))
-						}
-					}
-				}
-				m[src] = m2
+// gridProduct returns the product of an "id, x, y[, level[, floor]]" record
+func gridProduct(s []string) Product {
+	var temp [3]int
+	var err error
+	for i := range temp {
+		s[i] = strings.TrimSpace(s[i])
+		switch i {
+		case 0:
+			temp[i], err = strconv.Atoi(s[i])
+		default:
+			// coordinates may be written as floats, like 4.0, but must be whole
+			var f float64
+			f, err = strconv.ParseFloat(s[i], 64)
+			if err == nil && (f != math.Trunc(f) || math.IsInf(f, 0)) {
+				log.Fatalf("Item id %v has the coordinate %v, which is not a whole number.", s[0], s[i])
 			}
+			temp[i] = int(f)
+		}
+		if err != nil {
+			log.Fatal(err)
 		}
 	}
-	return m
+	temp[1], temp[2] = coordinateConverter(temp[1], temp[2])
+	prod := Product{id: temp[0], Pos: Point{temp[1], temp[2], 0}, pseudo: false}
+	if len(s) > 3 {
+		prod.Level, err = strconv.Atoi(strings.TrimSpace(s[3]))
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+	if len(s) > 4 {
+		prod.Pos.F, err = strconv.Atoi(strings.TrimSpace(s[4]))
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+	// the path info has no entry out of the grid, which would make the pick look free
+	if !layout.Contains(prod.Pos) {
+		log.Fatalf("Item id %v at %v is out of the warehouse of %v floor(s).", temp[0], prod.Pos, layout.Floors)
+	}
+	return prod
 }
 
-// ParesOrderInfo returns a list of orders
-func ParesOrderInfo(path string) []Order {
-	records, err := ReadCSV(path)
+// locatedProduct returns the product of an "id, location" record
+func locatedProduct(s []string) Product {
+	id, err := strconv.Atoi(strings.TrimSpace(s[0]))
+	if err != nil {
+		log.Fatal(err)
+	}
+	pos, level, err := ParseLocation(s[1])
 	if err != nil {
 		log.Fatal(err)
 	}
-	var orders []Order
-	for j, s := range records {
-		var err error
-		s = strings.Split(strings.TrimSpace(s[0]), "\t")
-		order := make(Order, len(s))
-		for i := range s {
-			s[i] = strings.TrimSpace(s[i])
-			order[i].ProdID, err = strconv.Atoi(s[i])
-			order[i].OrderID = j + 1
-			if err != nil {
-				log.Fatal(err)
+	if pos.X*pos.Y%2 == 0 {
+		log.Fatalf("Item id %v at %v is not on a shelf.", id, s[1])
+	}
+	return Product{id: id, Pos: pos, Level: level}
+}
+
+// BuildPathInfo return a nested map that records the travel costs between Points
+// under the TravelModel of the Router
+func BuildPathInfo(path string, r *Router) map[Point]map[Point]float64 {
+	var m map[Point]map[Point]float64
+	m = make(map[Point]map[Point]float64)
+	points := walkablePoints()
+	for _, src := range points {
+		var m2 map[Point]float64
+		m2 = make(map[Point]float64)
+		var tree *travelTree
+		if r.Model.searched() {
+			tree = r.Model.search(src)
+		}
+		for _, dest := range points {
+			if dest.F != src.F {
+				continue
+			}
+			if tree != nil {
+				m2[dest] = tree.costTo(dest)
+			} else {
+				m2[dest] = r.PathCost(FindPath(src, dest))
+			}
+		}
+		m[src] = m2
+	}
+	// across floors through the cheapest connector
+	for _, src := range points {
+		for _, dest := range points {
+			if dest.F != src.F {
+				m[src][dest] = crossFloorCost(m, src, dest)
 			}
 		}
-		orders = append(orders, order)
 	}
+	return m
+}
+
+// ParesOrderInfo returns a list of orders
+// from lines of item ids separated by tabs, or from any order file LoadOrders reads
+func ParesOrderInfo(path string) []Order {
+	orders, _ := LoadOrders(path)
 	return orders
 }
 
-// ParesDimensionInfo returns a list of item info: 
+// ParesDimensionInfo returns a list of item info:
 // map[Item_id]: [length width height weight]
+// from a file separated by tabs, commas or semicolons. A header naming the
+// columns maps them, the missing ones being 0; without one the columns are in
+// this order. An empty weight is unknown, NaN.
 func ParesDimensionInfo(path string) map[int][]float64 {
-	records, err := ReadCSV(path)
+	data, err := ioutil.ReadFile(path)
 	if err != nil {
 		log.Fatal(err)
 	}
-	items := make(map[int][]float64)
-	for _, s := range records[1:] {
-		var err error
-		s = strings.Split(strings.TrimSpace(s[0]), "\t")
-		item := make([]float64, len(s))
-		for i := range s {
-			s[i] = strings.TrimSpace(s[i])
-			item[i], err = strconv.ParseFloat(s[i], 64)
-			if err != nil {
-				log.Fatal(err)
+	records, _ := readDelimited(data)
+	cols := map[string]int{"id": 0, "length": 1, "width": 2, "height": 3, "weight": 4}
+	if len(records) > 0 {
+		if header := headerColumns(records[0], dimensionColumns); header != nil {
+			if _, ok := header["id"]; !ok {
+				log.Fatalf("Dimension file %v has no id column.", path)
 			}
+			cols = header
+			records = records[1:]
+		} else if _, err := strconv.ParseFloat(records[0][0], 64); err != nil {
+			// an unknown header
+			records = records[1:]
+		}
+	}
+	items := make(map[int][]float64)
+	for _, s := range records {
+		id := fieldInt(s, cols, "id", 0)
+		items[id] = []float64{
+			fieldFloat(s, cols, "length"),
+			fieldFloat(s, cols, "width"),
+			fieldFloat(s, cols, "height"),
+			math.NaN(),
+		}
+		if field(s, cols, "weight") != "" {
+			items[id][3] = fieldFloat(s, cols, "weight")
 		}
-		items[int(item[0])] = item[1:]
 	}
 	return items
 }
@@ -265,21 +324,31 @@ func BruteForceOrderOptimizer(o Order, start, end Point, m map[int]Product, path
 	var i sort.Interface = o
 	mathutil.PermutationFirst(i)
 	order := i.(Order)
+	ruled := constrained(order, m)
 	j := 0
 	minIndex := j
-	length := RouteLength(order, start, end, m, pathInfo)
+	length := RouteCost(order, start, end, m, pathInfo)
 	min := length
+	minViolations := len(PrecedenceViolations(order, m))
 	for {
 		ok := mathutil.PermutationNext(i)
 		if !ok {
 			break
 		}
 		order = i.(Order)
-		length = RouteLength(order, start, end, m, pathInfo)
 		j++
-		if min > math.Min(min, length) {
+		violations := 0
+		if ruled {
+			violations = len(PrecedenceViolations(order, m))
+			if violations > minViolations {
+				continue
+			}
+		}
+		length = RouteCost(order, start, end, m, pathInfo)
+		if violations < minViolations || min > math.Min(min, length) {
 			min = length
 			minIndex = j
+			minViolations = violations
 		}
 	}
 
@@ -295,6 +364,9 @@ func BruteForceOrderOptimizer(o Order, start, end Point, m map[int]Product, path
 
 // NearestNeighbourOrderOptimizer returns the Order by finding nearest neighbours
 func NearestNeighbourOrderOptimizer(o Order, start, end Point, m map[int]Product, pathInfo map[Point]map[Point]float64) Order {
+	if constrained(o, m) {
+		return precedenceOrderOptimizer(o, start, end, m, pathInfo)
+	}
 	var newOrder Order
 	ord := make(Order, len(o))
 	copy(ord, o)
@@ -302,12 +374,12 @@ func NearestNeighbourOrderOptimizer(o Order, start, end Point, m map[int]Product
 	for len(ord) > 0 {
 		minIndex := 0
 		dest := FindDest(src, m[ord[0].ProdID])
-		length := pathInfo[src][dest]
+		length := pathInfo[src][dest] + reachCost(m[ord[0].ProdID])
 		min := length
 		minDest := dest
 		for i, prod := range ord[1:] {
 			dest = FindDest(src, m[prod.ProdID])
-			length = pathInfo[src][dest]
+			length = pathInfo[src][dest] + reachCost(m[prod.ProdID])
 			if min > math.Min(min, length) {
 				min = length
 				minIndex = i + 1
@@ -324,6 +396,9 @@ func NearestNeighbourOrderOptimizer(o Order, start, end Point, m map[int]Product
 // NNIOrderOptimizer Nearest Neighbor With Iterations Order Optimizer.
 // If no iteration varible given then iteration == len(order)
 func NNIOrderOptimizer(o Order, start, end Point, m map[int]Product, pathInfo map[Point]map[Point]float64, iteration ...int) Order {
+	if constrained(o, m) {
+		return precedenceOrderOptimizer(o, start, end, m, pathInfo)
+	}
 	pseudoProd := Product{pseudo: true, pseudoIn: end, pseudoOut: start}
 	var newOrder Order
 	minTotal := math.Inf(1)
@@ -346,7 +421,7 @@ func NNIOrderOptimizer(o Order, start, end Point, m map[int]Product, pathInfo ma
 		if srcPoint.pseudo {
 			src = srcPoint.pseudoOut
 			nnOrder := nearestNeighborRing(ps, src, srcPoint, pathInfo)
-			length := RouteLength(nnOrder, start, end, m, pathInfo)
+			length := RouteCost(nnOrder, start, end, m, pathInfo)
 			if length < minTotal {
 				minTotal = length
 				newOrder = nnOrder
@@ -354,18 +429,18 @@ func NNIOrderOptimizer(o Order, start, end Point, m map[int]Product, pathInfo ma
 		} else {
 			// Maybe need to modify here (using FindDest to get src instead of hardcoding)
 			if srcPoint.l {
-				src = Point{srcPoint.Pos.X - 1, srcPoint.Pos.Y}
+				src = Point{srcPoint.Pos.X - 1, srcPoint.Pos.Y, srcPoint.Pos.F}
 				nnOrder := nearestNeighborRing(ps, src, srcPoint, pathInfo)
-				length := RouteLength(nnOrder, start, end, m, pathInfo)
+				length := RouteCost(nnOrder, start, end, m, pathInfo)
 				if length < minTotal {
 					minTotal = length
 					newOrder = nnOrder
 				}
 			}
 			if srcPoint.r {
-				src = Point{srcPoint.Pos.X + 1, srcPoint.Pos.Y}
+				src = Point{srcPoint.Pos.X + 1, srcPoint.Pos.Y, srcPoint.Pos.F}
 				nnOrder := nearestNeighborRing(ps, src, srcPoint, pathInfo)
-				length := RouteLength(nnOrder, start, end, m, pathInfo)
+				length := RouteCost(nnOrder, start, end, m, pathInfo)
 				if length < minTotal {
 					minTotal = length
 					newOrder = nnOrder
@@ -387,7 +462,7 @@ func nearestNeighborRing(prods []Product, src Point, srcProd Product, pathInfo m
 		var newSrc Point
 		for i, prod := range ps {
 			dest := FindDest(src, prod)
-			length = pathInfo[src][dest]
+			length = pathInfo[src][dest] + reachCost(prod)
 			if min > math.Min(min, length) {
 				min = length
 				minIndex = i
@@ -417,22 +492,39 @@ func nearestNeighborRing(prods []Product, src Point, srcProd Product, pathInfo m
 	return order
 }
 
-// RouteLength returns the length of the route for a specific Order
-func RouteLength(o Order, start, end Point, m map[int]Product, pathInfo map[Point]map[Point]float64) float64 {
+// RouteCost returns the cost of the route for a specific Order, the quantity the
+// optimizers minimize, as the sum of the travel costs in pathInfo and the reach
+// costs of the picks
+func RouteCost(o Order, start, end Point, m map[int]Product, pathInfo map[Point]map[Point]float64) float64 {
+	if len(o) == 0 {
+		return pathInfo[start][end]
+	}
 	var length float64
 	var prevPos Point
 	pos := FindDest(start, m[o[0].ProdID])
 	prevPos = pos
-	length += pathInfo[start][pos]
+	length += pathInfo[start][pos] + reachCost(m[o[0].ProdID])
 	for i := range o[1:len(o)] {
 		prevPos = pos
 		pos = FindDest(prevPos, m[o[i+1].ProdID])
-		length += pathInfo[prevPos][pos]
+		length += pathInfo[prevPos][pos] + reachCost(m[o[i+1].ProdID])
 	}
 	length += pathInfo[pos][end]
 	return length
 }
 
+// RouteLength returns the length in meters walked on the route for a specific Order,
+// without the turn penalties, direction factors and reach costs of its cost
+func RouteLength(o Order, start, end Point, m map[int]Product, r *Router) float64 {
+	return PathLength(routePath(o, start, end, m, r))
+}
+
+// reachCost returns the cost of reaching the level of a product to pick it,
+// charged on the leg arriving at the product
+func reachCost(prod Product) float64 {
+	return float64(prod.Level) * layout.LevelCost
+}
+
 // RouteEffort returns the total effort of a specific Order
 func RouteEffort(o Order, start, end Point, m map[int]Product, pathInfo map[Point]map[Point]float64) (float64, bool) {
 	var effort float64
@@ -476,14 +568,19 @@ func FindDest(src Point, prod Product) Point {
 		return prod.pseudoIn
 	}
 	if src.X < prod.Pos.X {
-		return Point{prod.Pos.X - 1, prod.Pos.Y}
+		return Point{prod.Pos.X - 1, prod.Pos.Y, prod.Pos.F}
 	}
-	return Point{prod.Pos.X + 1, prod.Pos.Y}
+	return Point{prod.Pos.X + 1, prod.Pos.Y, prod.Pos.F}
 }
 
 // FindPath returns the array of turning points on the path
 // inclduing source and destination
+// Paths to another floor go through a connector and change F at it.
+// Router.FindPath finds the paths under a TravelModel.
 func FindPath(src Point, dest Point) Path {
+	if src.F != dest.F {
+		return (&Router{}).findPathFloors(src, dest)
+	}
 	var path Path
 	switch {
 	case src.X == dest.X && src.Y == dest.Y:
@@ -491,79 +588,206 @@ func FindPath(src Point, dest Point) Path {
 		path = []Point{src, dest}
 	case src.Y%2 == 1 && src.Y < dest.Y:
 		if src.Y + 1 == dest.Y {
-			path = []Point{src, {src.X, src.Y + 1}, dest}
+			path = []Point{src, {src.X, src.Y + 1, src.F}, dest}
 		} else {
-			path = []Point{src, {src.X, src.Y + 1}, {dest.X, src.Y + 1}, dest}
+			path = []Point{src, {src.X, src.Y + 1, src.F}, {dest.X, src.Y + 1, src.F}, dest}
 		}
 	case src.Y%2 == 1 && src.Y >= dest.Y:
 		if src.Y - 1 == dest.Y {
-			path = []Point{src, {src.X, src.Y - 1}, dest}
+			path = []Point{src, {src.X, src.Y - 1, src.F}, dest}
 		} else {
-			path = []Point{src, {src.X, src.Y - 1}, {dest.X, src.Y - 1}, dest}
+			path = []Point{src, {src.X, src.Y - 1, src.F}, {dest.X, src.Y - 1, src.F}, dest}
 		}
 	case src.Y == dest.Y:
 		path = []Point{src, dest}
 	default:
-		path = []Point{src, {dest.X, src.Y}, dest}
+		path = []Point{src, {dest.X, src.Y, src.F}, dest}
 	}
 	return path
 }
 
-// PathLength returns the length of the path
+// PathLength returns the length of the path in meters of the Layout,
+// not counting the rides between floors; +Inf if the path leaves the grid
 func PathLength(path Path) float64 {
 	if cap(path) < 1 {
 		return 0.0
 	}
 	var dx, dy float64
 	for i := range path[1:] {
-		dx += math.Abs(float64(path[i+1].X-path[i].X)) * (shelfLength + pathWidthX) / 2
-		dy += math.Abs(float64(path[i+1].Y-path[i].Y)) * (shelfWidth + pathWidthY) / 2
+		x1, y1, err1 := layout.Meters(path[i])
+		x2, y2, err2 := layout.Meters(path[i+1])
+		if err1 != nil || err2 != nil {
+			return math.Inf(1)
+		}
+		dx += math.Abs(x2 - x1)
+		dy += math.Abs(y2 - y1)
 	}
 	return dx + dy
 }
 
-// Route2String returns the string representation of the route
-func Route2String(order Order, start, end Point, m map[int]Product) string {
+// pickString returns the pick of the item at dest, e.g. "[pick up {108 1} from A03-B02R-L1 at A04-B02]"
+func pickString(item Item, prod Product, dest Point) string {
+	return fmt.Sprintf("[pick up %v from %v at %v]", item, ProductLocation(prod), LocationName(dest))
+}
+
+// Route2String returns the string representation of the route,
+// every pick told by the location names of the shelf and of the aisle cell it is made from
+func Route2String(order Order, start, end Point, m map[int]Product, r *Router) string {
 	dest := FindDest(start, m[order[0].ProdID])
-	s := fmt.Sprintf("%v->", FindPath(start, dest))
-	s += fmt.Sprintf("[pick up %v from %v]->", order[0], m[order[0].ProdID].Pos)
+	s := fmt.Sprintf("%v->", r.FindPath(start, dest))
+	s += pickString(order[0], m[order[0].ProdID], dest) + "->"
 	var src Point
 	for _, prod := range order[1:] {
 		src = dest
 		dest = FindDest(src, m[prod.ProdID])
-		s += fmt.Sprintf("%v->", FindPath(src, dest))
-		s += fmt.Sprintf("[pick up %v from %v]->", prod, m[prod.ProdID].Pos)
+		s += fmt.Sprintf("%v->", r.FindPath(src, dest))
+		s += pickString(prod, m[prod.ProdID], dest) + "->"
 	}
 	src = dest
-	s += fmt.Sprint(FindPath(src, end))
+	s += fmt.Sprint(r.FindPath(src, end))
 	return s
 }
 
+// ANSI escapes of the colours of Route2Map
+const (
+	ansiReset = "\x1b[0m"
+	ansiShelf = "\x1b[2m"
+	ansiPath  = "\x1b[36m"
+	ansiPick  = "\x1b[1;33m"
+	ansiDepot = "\x1b[1;32m"
+)
+
+// Route2Map returns the route drawn on the grid for a terminal, the highest Y on top.
+// Every cell takes two characters, or more when the number of the last pick needs
+// them: shelves are "##", aisles are blank, the path is traced with arrows, picked
+// shelves show the number of the pick, and S and E mark the start and the end, SE both
+// when the route ends where it starts. Every floor the route passes shows its own map; with color
+// the map is coloured with ANSI escapes.
+func Route2Map(order Order, start, end Point, m map[int]Product, r *Router, color bool) string {
+	path := routePath(order, start, end, m, r)
+	width := len(strconv.Itoa(len(order)))
+	if width < 2 {
+		width = 2
+	}
+	cell := func(s string) string {
+		return fmt.Sprintf("%-*s", width, s)
+	}
+	cells := make(map[Point]string)
+	for i := 1; i < len(path); i++ {
+		a, b := path[i-1], path[i]
+		if a.F != b.F {
+			continue
+		}
+		for a != b {
+			step, arrow := a, ""
+			switch {
+			case a.X < b.X:
+				step.X, arrow = a.X+1, ">"
+			case a.X > b.X:
+				step.X, arrow = a.X-1, "<"
+			case a.Y < b.Y:
+				step.Y, arrow = a.Y+1, "^"
+			default:
+				step.Y, arrow = a.Y-1, "v"
+			}
+			cells[a] = cell(arrow)
+			a = step
+		}
+	}
+	for i, item := range order {
+		cells[m[item.ProdID].Pos] = cell(strconv.Itoa(i + 1))
+	}
+	cells[start], cells[end] = cell("S"), cell("E")
+	if start == end {
+		cells[start] = cell("SE")
+	}
+
+	floors := make(map[int]bool)
+	for _, p := range append(path, start, end) {
+		floors[p.F] = true
+	}
+	paint := func(c, s string) string {
+		if !color {
+			return s
+		}
+		return c + s + ansiReset
+	}
+	var b strings.Builder
+	for f := 0; f < layout.Floors; f++ {
+		if !floors[f] {
+			continue
+		}
+		if layout.Floors > 1 {
+			fmt.Fprintf(&b, "Floor %v:\n", f)
+		}
+		for y := layout.MaxY; y >= 0; y-- {
+			for x := 0; x <= layout.MaxX; x++ {
+				p := Point{x, y, f}
+				c, ok := cells[p]
+				switch {
+				case p == start || p == end:
+					b.WriteString(paint(ansiDepot, c))
+				case ok && x*y%2 == 1:
+					b.WriteString(paint(ansiPick, c))
+				case ok:
+					b.WriteString(paint(ansiPath, c))
+				case x*y%2 == 1:
+					b.WriteString(paint(ansiShelf, strings.Repeat("#", width)))
+				default:
+					b.WriteString(cell(""))
+				}
+			}
+			b.WriteString("\n")
+		}
+	}
+	return b.String()
+}
+
+// RouteOrder is the JSON encoding of the trips of a batch.
+// Lengths are the lengths in meters of the Paths.
+// Every point of the Paths carries its floor F; a lift or stairs ride
+// shows as two points differing only in F.
+// Starts and Ends are the depots chosen for every trip; Start and End are those of the first.
 type RouteOrder struct {
 	Paths []Path
 	Products [][]Product
 	Orders []Order
 	Start, End Point
+	Lengths []float64
+	Starts, Ends []Point
 }
 
 // Orders2Routes returns the JSON encoding
-func Orders2Routes(orders []Order, start, end Point, m map[int]Product) RouteOrder {
+func Orders2Routes(orders []Order, start, end Point, m map[int]Product, r *Router) RouteOrder {
+	starts := make([]Point, len(orders))
+	ends := make([]Point, len(orders))
+	for i := range orders {
+		starts[i], ends[i] = start, end
+	}
+	return Orders2RoutesDepots(orders, starts, ends, m, r)
+}
+
+// Orders2RoutesDepots returns the JSON encoding of trips with their own depots
+func Orders2RoutesDepots(orders []Order, starts, ends []Point, m map[int]Product, r *Router) RouteOrder {
 	var paths []Path
 	var products [][]Product
-	for _, order := range orders{
+	var lengths []float64
+	for i, order := range orders{
 		var path Path
 		var product []Product
+		start, end := starts[i], ends[i]
 		dest := FindDest(start, m[order[0].ProdID])
-		path = append(path, FindPath(start, dest)...)
+		path = append(path, r.FindPath(start, dest)...)
 		var src Point
 		for _, prod := range order[1:] {
 			src = dest
 			dest = FindDest(src, m[prod.ProdID])
-			path = append(path, FindPath(src, dest)...)
+			path = append(path, r.FindPath(src, dest)...)
 		}
 		src = dest
-		path = append(path, FindPath(src, end)...)
+		path = append(path, r.FindPath(src, end)...)
 		paths = append(paths, path)
+		lengths = append(lengths, PathLength(path))
 		for _, prod := range order {
 			p := m[prod.ProdID]
 			p.OrderID = prod.OrderID
@@ -572,7 +796,11 @@ func Orders2Routes(orders []Order, start, end Point, m map[int]Product) RouteOrd
 		products = append(products, product)
 	}
 
-	ro := RouteOrder{paths, products, orders, start, end}
+	var start, end Point
+	if len(orders) > 0 {
+		start, end = starts[0], ends[0]
+	}
+	ro := RouteOrder{paths, products, orders, start, end, lengths, starts, ends}
 	return ro
 	/*b, err := json.Marshal(ro)
 	if err != nil {
@@ -582,6 +810,9 @@ func Orders2Routes(orders []Order, start, end Point, m map[int]Product) RouteOrd
 }
 
 func (p Point) String() string {
+	if p.F != 0 {
+		return fmt.Sprintf("(%d, %d, F%d)", p.X, p.Y, p.F)
+	}
 	return fmt.Sprintf("(%d, %d)", p.X, p.Y)
 }
 
@@ -0,0 +1,60 @@
+package warehouse
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestRoute2MapWideLabels(t *testing.T) {
+	l := DefaultLayout
+	l.MaxX, l.MaxY = 8, 8
+	SetLayout(l)
+	t.Cleanup(func() { SetLayout(DefaultLayout) })
+	var grid strings.Builder
+	var o Order
+	for id := 1; id <= 100; id++ {
+		fmt.Fprintf(&grid, "%v, %v, %v\n", id, id%4, id/4%4)
+		o = append(o, Item{id, 1})
+	}
+	m := ParseProductInfo(writeInput(t, "grid.csv", grid.String()), nil)
+	s := Route2Map(o, Point{0, 0, 0}, Point{8, 8, 0}, m, NewRouter(TravelModel{}), false)
+	lines := strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+	if len(lines) != l.MaxY+1 {
+		t.Fatalf("%v lines, want %v:\n%v", len(lines), l.MaxY+1, s)
+	}
+	for _, line := range lines {
+		if n := utf8.RuneCountInString(line); n != 3*(l.MaxX+1) {
+			t.Errorf("line %q is %v characters, want 3 per cell", line, n)
+		}
+	}
+	// pick 100 is the last one on its shelf, at (0, 1) counted in shelves
+	if got := lines[l.MaxY-3][3:6]; got != "100" {
+		t.Errorf("shelf of pick 100 shows %q:\n%v", got, s)
+	}
+}
+
+func TestRoute2MapDepots(t *testing.T) {
+	m := testProducts(t)
+	r := NewRouter(TravelModel{})
+	o := Order{{1, 1}}
+	for _, tc := range []struct {
+		name       string
+		start, end Point
+		want       map[Point]string
+	}{
+		{"apart", Point{0, 0, 0}, Point{8, 8, 0}, map[Point]string{{0, 0, 0}: "S ", {8, 8, 0}: "E "}},
+		{"same", Point{2, 0, 0}, Point{2, 0, 0}, map[Point]string{{2, 0, 0}: "SE"}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			s := Route2Map(o, tc.start, tc.end, m, r, false)
+			lines := strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+			for p, want := range tc.want {
+				if got := lines[8-p.Y][2*p.X : 2*p.X+2]; got != want {
+					t.Errorf("%v shows %q, want %q:\n%v", p, got, want, s)
+				}
+			}
+		})
+	}
+}
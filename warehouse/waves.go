@@ -0,0 +1,163 @@
+package warehouse
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"log"
+	"os"
+	"strings"
+	"time"
+)
+
+// WaveConfig defines when the wave planner releases a wave: every Cadence seconds,
+// or as soon as Threshold orders are waiting (0 for no threshold)
+type WaveConfig struct {
+	Cadence   float64
+	Threshold int
+}
+
+// DefaultWaveConfig is the wave config used when no config file is given
+var DefaultWaveConfig = WaveConfig{Cadence: 60, Threshold: 20}
+
+// LoadWaveConfig returns the wave config from a config file of
+// "cadence, seconds" and "threshold, orders" lines
+func LoadWaveConfig(path string) WaveConfig {
+	cfg := DefaultWaveConfig
+	records, ok := readConfig(path)
+	if !ok {
+		return cfg
+	}
+	for _, rec := range records {
+		switch rec[0] {
+		case "cadence":
+			cfg.Cadence = configFloat(rec, 1)
+		case "threshold":
+			cfg.Threshold = configInt(rec, 1)
+		default:
+			log.Fatalf("Unknown wave config entry %q.", rec[0])
+		}
+	}
+	if cfg.Cadence <= 0 {
+		log.Fatal("The wave cadence must be positive.")
+	}
+	return cfg
+}
+
+// StreamOrder is an order of the stream, one JSON object per line,
+// e.g. {"OrderID": 12, "Items": [108335, 391825]}
+type StreamOrder struct {
+	OrderID int
+	Items   []int
+}
+
+// Wave is a set of orders released together and its routes
+type Wave struct {
+	Number   int
+	Released time.Time
+	Orders   int
+	Routes   []RouteOrder
+}
+
+// parseStreamLine returns the Order of a line of the stream.
+// Malformed lines and unknown items are logged and skipped so the stream keeps going.
+func parseStreamLine(line string, m map[int]Product) (Order, bool) {
+	line = strings.TrimSpace(line)
+	if line == "" {
+		return nil, false
+	}
+	var so StreamOrder
+	if err := json.Unmarshal([]byte(line), &so); err != nil {
+		log.Printf("Skipping order %q: %v", line, err)
+		return nil, false
+	}
+	if len(so.Items) == 0 {
+		log.Printf("Skipping order %v: no items.", so.OrderID)
+		return nil, false
+	}
+	var o Order
+	for _, id := range so.Items {
+		if _, ok := m[id]; !ok {
+			log.Printf("Skipping order %v: item id %v not exist.", so.OrderID, id)
+			return nil, false
+		}
+		o = append(o, Item{id, so.OrderID})
+	}
+	return o, true
+}
+
+// ReadOrderStream sends the orders read from r, one JSON object per line, until r ends
+// and then closes out
+func ReadOrderStream(r io.Reader, m map[int]Product, out chan<- Order) {
+	defer close(out)
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		if o, ok := parseStreamLine(scanner.Text(), m); ok {
+			out <- o
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// TailOrderStream sends the orders appended to the file at path, like tail -f.
+// It checks the file for new lines every poll and never returns.
+func TailOrderStream(path string, poll time.Duration, m map[int]Product, out chan<- Order) {
+	file, err := os.Open(path)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer file.Close()
+	r := bufio.NewReader(file)
+	var partial string
+	for {
+		line, err := r.ReadString('\n')
+		partial += line
+		if err == io.EOF {
+			time.Sleep(poll)
+			continue
+		}
+		if err != nil {
+			log.Fatal(err)
+		}
+		if o, ok := parseStreamLine(partial, m); ok {
+			out <- o
+		}
+		partial = ""
+	}
+}
+
+// PlanWaves collects the orders of in and releases them in waves as configured.
+// Every wave is planned by plan and passed to emit as soon as it is released.
+// When in is closed the waiting orders are released as a last wave.
+func PlanWaves(in <-chan Order, cfg WaveConfig, plan func([]Order) []RouteOrder, emit func(Wave)) {
+	ticker := time.NewTicker(time.Duration(cfg.Cadence * float64(time.Second)))
+	defer ticker.Stop()
+	var waiting []Order
+	n := 0
+	release := func() {
+		if len(waiting) == 0 {
+			return
+		}
+		n++
+		orders := waiting
+		waiting = nil
+		emit(Wave{Number: n, Released: time.Now(), Orders: len(orders), Routes: plan(orders)})
+	}
+	for {
+		select {
+		case o, ok := <-in:
+			if !ok {
+				release()
+				return
+			}
+			waiting = append(waiting, o)
+			if cfg.Threshold > 0 && len(waiting) >= cfg.Threshold {
+				release()
+			}
+		case <-ticker.C:
+			release()
+		}
+	}
+}
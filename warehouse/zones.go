@@ -0,0 +1,91 @@
+package warehouse
+
+import (
+	"fmt"
+)
+
+// Zone is a rectangle of the grid on floor F with dedicated pickers.
+// Totes enter and leave the zone on the conveyor at its Induct point.
+type Zone struct {
+	Name           string
+	X1, Y1, X2, Y2 int
+	F              int
+	Induct         Point
+}
+
+// Contains reports whether the point lies in the zone
+func (z Zone) Contains(p Point) bool {
+	return p.F == z.F && p.X >= z.X1 && p.X <= z.X2 && p.Y >= z.Y1 && p.Y <= z.Y2
+}
+
+// ZoneRoute is the route of the parts of the orders picked in one zone,
+// one trip per order
+type ZoneRoute struct {
+	Zone  string
+	Route RouteOrder
+}
+
+// ZoneFlow is the sequence of zones the tote of an order passes through
+type ZoneFlow struct {
+	OrderID int      `json:"order_id"`
+	Zones   []string `json:"zones"`
+}
+
+// FindZone returns the index of the zone holding the product
+func FindZone(prod Product) (int, bool) {
+	for i, z := range layout.Zones {
+		if z.Contains(prod.Pos) {
+			return i, true
+		}
+	}
+	return -1, false
+}
+
+// SplitOrderByZone returns the parts of the order picked in every zone,
+// indexed like the zones of the Layout
+func SplitOrderByZone(o Order, m map[int]Product) ([]Order, error) {
+	parts := make([]Order, len(layout.Zones))
+	for _, item := range o {
+		z, ok := FindZone(m[item.ProdID])
+		if !ok {
+			return nil, fmt.Errorf("item id %v is in no zone", item.ProdID)
+		}
+		parts[z] = append(parts[z], item)
+	}
+	return parts, nil
+}
+
+// PlanZones splits every order by zone (pick-and-pass) and routes every part
+// with opt from the induct point of its zone back to it.
+// Zones are passed in the order of the Layout, which is the order of the conveyor.
+func PlanZones(orders []Order, m map[int]Product, r *Router, pathInfo map[Point]map[Point]float64, opt Optimizer) ([]ZoneRoute, []ZoneFlow, error) {
+	if len(layout.Zones) == 0 {
+		return nil, nil, fmt.Errorf("the layout has no zones")
+	}
+	trips := make([][]Order, len(layout.Zones))
+	var flows []ZoneFlow
+	for _, o := range orders {
+		flow := ZoneFlow{OrderID: o[0].OrderID}
+		parts, err := SplitOrderByZone(o, m)
+		if err != nil {
+			return nil, nil, err
+		}
+		for z, part := range parts {
+			if len(part) == 0 {
+				continue
+			}
+			induct := layout.Zones[z].Induct
+			trips[z] = append(trips[z], opt(part, induct, induct, m, pathInfo))
+			flow.Zones = append(flow.Zones, layout.Zones[z].Name)
+		}
+		flows = append(flows, flow)
+	}
+	var routes []ZoneRoute
+	for z, zone := range layout.Zones {
+		if len(trips[z]) == 0 {
+			continue
+		}
+		routes = append(routes, ZoneRoute{zone.Name, Orders2Routes(trips[z], zone.Induct, zone.Induct, m, r)})
+	}
+	return routes, flows, nil
+}
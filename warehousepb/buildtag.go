@@ -0,0 +1,34 @@
+//go:build ignore
+// +build ignore
+
+// buildtag puts the generated .pb.go files of the directory behind the grpc build
+// tag, so that the optimizer builds without the protobuf and gRPC modules.
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"log"
+	"path/filepath"
+)
+
+const constraint = "//go:build grpc\n// +build grpc\n\n"
+
+func main() {
+	paths, err := filepath.Glob("*.pb.go")
+	if err != nil {
+		log.Fatal(err)
+	}
+	for _, path := range paths {
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if bytes.HasPrefix(data, []byte(constraint)) {
+			continue
+		}
+		if err := ioutil.WriteFile(path, append([]byte(constraint), data...), 0644); err != nil {
+			log.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,14 @@
+// Package warehousepb holds the protobuf messages and the gRPC service of the optimizer.
+// The Go code is generated from warehouse.proto and put behind the grpc build tag, so
+// without -tags grpc the package is empty and needs no module besides the standard library.
+// It was generated with protoc-gen-go v1.36.9 and protoc-gen-go-grpc v1.5.1, and with
+// -tags grpc needs google.golang.org/protobuf v1.36.9 and google.golang.org/grpc v1.64.0
+// or later:
+//
+//	go install google.golang.org/protobuf/cmd/protoc-gen-go@v1.36.9
+//	go install google.golang.org/grpc/cmd/protoc-gen-go-grpc@v1.5.1
+//	go generate ./warehousepb
+package warehousepb
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative warehouse.proto
+//go:generate go run buildtag.go
@@ -0,0 +1,1575 @@
+//go:build grpc
+// +build grpc
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.9
+// 	protoc        (unknown)
+// source: warehouse.proto
+
+package warehousepb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Optimizer int32
+
+const (
+	Optimizer_OPTIMIZER_NNI Optimizer = 0
+	Optimizer_OPTIMIZER_BNB Optimizer = 1
+)
+
+// Enum value maps for Optimizer.
+var (
+	Optimizer_name = map[int32]string{
+		0: "OPTIMIZER_NNI",
+		1: "OPTIMIZER_BNB",
+	}
+	Optimizer_value = map[string]int32{
+		"OPTIMIZER_NNI": 0,
+		"OPTIMIZER_BNB": 1,
+	}
+)
+
+func (x Optimizer) Enum() *Optimizer {
+	p := new(Optimizer)
+	*p = x
+	return p
+}
+
+func (x Optimizer) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Optimizer) Descriptor() protoreflect.EnumDescriptor {
+	return file_warehouse_proto_enumTypes[0].Descriptor()
+}
+
+func (Optimizer) Type() protoreflect.EnumType {
+	return &file_warehouse_proto_enumTypes[0]
+}
+
+func (x Optimizer) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Optimizer.Descriptor instead.
+func (Optimizer) EnumDescriptor() ([]byte, []int) {
+	return file_warehouse_proto_rawDescGZIP(), []int{0}
+}
+
+type Objective int32
+
+const (
+	// OBJECTIVE_DISTANCE minimizes the travel cost: the distance with its turn
+	// penalties, direction factors and level costs.
+	Objective_OBJECTIVE_DISTANCE Objective = 0
+	// OBJECTIVE_TIME minimizes the time of the walk and of the pick stops.
+	Objective_OBJECTIVE_TIME Objective = 1
+)
+
+// Enum value maps for Objective.
+var (
+	Objective_name = map[int32]string{
+		0: "OBJECTIVE_DISTANCE",
+		1: "OBJECTIVE_TIME",
+	}
+	Objective_value = map[string]int32{
+		"OBJECTIVE_DISTANCE": 0,
+		"OBJECTIVE_TIME":     1,
+	}
+)
+
+func (x Objective) Enum() *Objective {
+	p := new(Objective)
+	*p = x
+	return p
+}
+
+func (x Objective) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Objective) Descriptor() protoreflect.EnumDescriptor {
+	return file_warehouse_proto_enumTypes[1].Descriptor()
+}
+
+func (Objective) Type() protoreflect.EnumType {
+	return &file_warehouse_proto_enumTypes[1]
+}
+
+func (x Objective) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Objective.Descriptor instead.
+func (Objective) EnumDescriptor() ([]byte, []int) {
+	return file_warehouse_proto_rawDescGZIP(), []int{1}
+}
+
+// Point is a cell of the grid on floor f. Responses name it, e.g. A07-B04R, except
+// in paths; a request may give the name instead of x, y and f.
+type Point struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	X             int32                  `protobuf:"varint,1,opt,name=x,proto3" json:"x,omitempty"`
+	Y             int32                  `protobuf:"varint,2,opt,name=y,proto3" json:"y,omitempty"`
+	F             int32                  `protobuf:"varint,3,opt,name=f,proto3" json:"f,omitempty"`
+	Name          string                 `protobuf:"bytes,4,opt,name=name,proto3" json:"name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Point) Reset() {
+	*x = Point{}
+	mi := &file_warehouse_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Point) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Point) ProtoMessage() {}
+
+func (x *Point) ProtoReflect() protoreflect.Message {
+	mi := &file_warehouse_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Point.ProtoReflect.Descriptor instead.
+func (*Point) Descriptor() ([]byte, []int) {
+	return file_warehouse_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Point) GetX() int32 {
+	if x != nil {
+		return x.X
+	}
+	return 0
+}
+
+func (x *Point) GetY() int32 {
+	if x != nil {
+		return x.Y
+	}
+	return 0
+}
+
+func (x *Point) GetF() int32 {
+	if x != nil {
+		return x.F
+	}
+	return 0
+}
+
+func (x *Point) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+// Item is a product of an order
+type Item struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ProdId        int32                  `protobuf:"varint,1,opt,name=prod_id,json=prodId,proto3" json:"prod_id,omitempty"`
+	OrderId       int32                  `protobuf:"varint,2,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Item) Reset() {
+	*x = Item{}
+	mi := &file_warehouse_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Item) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Item) ProtoMessage() {}
+
+func (x *Item) ProtoReflect() protoreflect.Message {
+	mi := &file_warehouse_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Item.ProtoReflect.Descriptor instead.
+func (*Item) Descriptor() ([]byte, []int) {
+	return file_warehouse_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Item) GetProdId() int32 {
+	if x != nil {
+		return x.ProdId
+	}
+	return 0
+}
+
+func (x *Item) GetOrderId() int32 {
+	if x != nil {
+		return x.OrderId
+	}
+	return 0
+}
+
+type Order struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Items         []*Item                `protobuf:"bytes,1,rep,name=items,proto3" json:"items,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Order) Reset() {
+	*x = Order{}
+	mi := &file_warehouse_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Order) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Order) ProtoMessage() {}
+
+func (x *Order) ProtoReflect() protoreflect.Message {
+	mi := &file_warehouse_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Order.ProtoReflect.Descriptor instead.
+func (*Order) Descriptor() ([]byte, []int) {
+	return file_warehouse_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Order) GetItems() []*Item {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+// Product is a product on its shelf
+type Product struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Pos           *Point                 `protobuf:"bytes,2,opt,name=pos,proto3" json:"pos,omitempty"`
+	Level         int32                  `protobuf:"varint,3,opt,name=level,proto3" json:"level,omitempty"`
+	Class         string                 `protobuf:"bytes,4,opt,name=class,proto3" json:"class,omitempty"`
+	OrderId       int32                  `protobuf:"varint,5,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Product) Reset() {
+	*x = Product{}
+	mi := &file_warehouse_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Product) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Product) ProtoMessage() {}
+
+func (x *Product) ProtoReflect() protoreflect.Message {
+	mi := &file_warehouse_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Product.ProtoReflect.Descriptor instead.
+func (*Product) Descriptor() ([]byte, []int) {
+	return file_warehouse_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Product) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Product) GetPos() *Point {
+	if x != nil {
+		return x.Pos
+	}
+	return nil
+}
+
+func (x *Product) GetLevel() int32 {
+	if x != nil {
+		return x.Level
+	}
+	return 0
+}
+
+func (x *Product) GetClass() string {
+	if x != nil {
+		return x.Class
+	}
+	return ""
+}
+
+func (x *Product) GetOrderId() int32 {
+	if x != nil {
+		return x.OrderId
+	}
+	return 0
+}
+
+type Path struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Points        []*Point               `protobuf:"bytes,1,rep,name=points,proto3" json:"points,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Path) Reset() {
+	*x = Path{}
+	mi := &file_warehouse_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Path) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Path) ProtoMessage() {}
+
+func (x *Path) ProtoReflect() protoreflect.Message {
+	mi := &file_warehouse_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Path.ProtoReflect.Descriptor instead.
+func (*Path) Descriptor() ([]byte, []int) {
+	return file_warehouse_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *Path) GetPoints() []*Point {
+	if x != nil {
+		return x.Points
+	}
+	return nil
+}
+
+// Trip is one trip of a RouteOrder, walked from start to end
+type Trip struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Order         *Order                 `protobuf:"bytes,1,opt,name=order,proto3" json:"order,omitempty"`
+	Products      []*Product             `protobuf:"bytes,2,rep,name=products,proto3" json:"products,omitempty"`
+	Path          *Path                  `protobuf:"bytes,3,opt,name=path,proto3" json:"path,omitempty"`
+	Length        float64                `protobuf:"fixed64,4,opt,name=length,proto3" json:"length,omitempty"`
+	Start         *Point                 `protobuf:"bytes,5,opt,name=start,proto3" json:"start,omitempty"`
+	End           *Point                 `protobuf:"bytes,6,opt,name=end,proto3" json:"end,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Trip) Reset() {
+	*x = Trip{}
+	mi := &file_warehouse_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Trip) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Trip) ProtoMessage() {}
+
+func (x *Trip) ProtoReflect() protoreflect.Message {
+	mi := &file_warehouse_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Trip.ProtoReflect.Descriptor instead.
+func (*Trip) Descriptor() ([]byte, []int) {
+	return file_warehouse_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *Trip) GetOrder() *Order {
+	if x != nil {
+		return x.Order
+	}
+	return nil
+}
+
+func (x *Trip) GetProducts() []*Product {
+	if x != nil {
+		return x.Products
+	}
+	return nil
+}
+
+func (x *Trip) GetPath() *Path {
+	if x != nil {
+		return x.Path
+	}
+	return nil
+}
+
+func (x *Trip) GetLength() float64 {
+	if x != nil {
+		return x.Length
+	}
+	return 0
+}
+
+func (x *Trip) GetStart() *Point {
+	if x != nil {
+		return x.Start
+	}
+	return nil
+}
+
+func (x *Trip) GetEnd() *Point {
+	if x != nil {
+		return x.End
+	}
+	return nil
+}
+
+type RouteOrder struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Trips         []*Trip                `protobuf:"bytes,1,rep,name=trips,proto3" json:"trips,omitempty"`
+	Start         *Point                 `protobuf:"bytes,2,opt,name=start,proto3" json:"start,omitempty"`
+	End           *Point                 `protobuf:"bytes,3,opt,name=end,proto3" json:"end,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RouteOrder) Reset() {
+	*x = RouteOrder{}
+	mi := &file_warehouse_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RouteOrder) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RouteOrder) ProtoMessage() {}
+
+func (x *RouteOrder) ProtoReflect() protoreflect.Message {
+	mi := &file_warehouse_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RouteOrder.ProtoReflect.Descriptor instead.
+func (*RouteOrder) Descriptor() ([]byte, []int) {
+	return file_warehouse_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *RouteOrder) GetTrips() []*Trip {
+	if x != nil {
+		return x.Trips
+	}
+	return nil
+}
+
+func (x *RouteOrder) GetStart() *Point {
+	if x != nil {
+		return x.Start
+	}
+	return nil
+}
+
+func (x *RouteOrder) GetEnd() *Point {
+	if x != nil {
+		return x.End
+	}
+	return nil
+}
+
+// RouteRequest asks for the route of one order.
+// Without start and end the route starts and ends at the best depots.
+type RouteRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Order         *Order                 `protobuf:"bytes,1,opt,name=order,proto3" json:"order,omitempty"`
+	Start         *Point                 `protobuf:"bytes,2,opt,name=start,proto3" json:"start,omitempty"`
+	End           *Point                 `protobuf:"bytes,3,opt,name=end,proto3" json:"end,omitempty"`
+	Optimizer     Optimizer              `protobuf:"varint,4,opt,name=optimizer,proto3,enum=warehouse.Optimizer" json:"optimizer,omitempty"`
+	Objective     Objective              `protobuf:"varint,5,opt,name=objective,proto3,enum=warehouse.Objective" json:"objective,omitempty"`
+	TimeLimit     float64                `protobuf:"fixed64,6,opt,name=time_limit,json=timeLimit,proto3" json:"time_limit,omitempty"` // seconds, 0 for the server's limit
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RouteRequest) Reset() {
+	*x = RouteRequest{}
+	mi := &file_warehouse_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RouteRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RouteRequest) ProtoMessage() {}
+
+func (x *RouteRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_warehouse_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RouteRequest.ProtoReflect.Descriptor instead.
+func (*RouteRequest) Descriptor() ([]byte, []int) {
+	return file_warehouse_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *RouteRequest) GetOrder() *Order {
+	if x != nil {
+		return x.Order
+	}
+	return nil
+}
+
+func (x *RouteRequest) GetStart() *Point {
+	if x != nil {
+		return x.Start
+	}
+	return nil
+}
+
+func (x *RouteRequest) GetEnd() *Point {
+	if x != nil {
+		return x.End
+	}
+	return nil
+}
+
+func (x *RouteRequest) GetOptimizer() Optimizer {
+	if x != nil {
+		return x.Optimizer
+	}
+	return Optimizer_OPTIMIZER_NNI
+}
+
+func (x *RouteRequest) GetObjective() Objective {
+	if x != nil {
+		return x.Objective
+	}
+	return Objective_OBJECTIVE_DISTANCE
+}
+
+func (x *RouteRequest) GetTimeLimit() float64 {
+	if x != nil {
+		return x.TimeLimit
+	}
+	return 0
+}
+
+type RouteResponse struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	Route      *RouteOrder            `protobuf:"bytes,1,opt,name=route,proto3" json:"route,omitempty"`
+	Length     float64                `protobuf:"fixed64,2,opt,name=length,proto3" json:"length,omitempty"`
+	Duration   float64                `protobuf:"fixed64,3,opt,name=duration,proto3" json:"duration,omitempty"`
+	Violations []string               `protobuf:"bytes,4,rep,name=violations,proto3" json:"violations,omitempty"`
+	// walking directions, e.g. "walk up aisle 7 to bay 4"
+	Directions    []string `protobuf:"bytes,5,rep,name=directions,proto3" json:"directions,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RouteResponse) Reset() {
+	*x = RouteResponse{}
+	mi := &file_warehouse_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RouteResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RouteResponse) ProtoMessage() {}
+
+func (x *RouteResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_warehouse_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RouteResponse.ProtoReflect.Descriptor instead.
+func (*RouteResponse) Descriptor() ([]byte, []int) {
+	return file_warehouse_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *RouteResponse) GetRoute() *RouteOrder {
+	if x != nil {
+		return x.Route
+	}
+	return nil
+}
+
+func (x *RouteResponse) GetLength() float64 {
+	if x != nil {
+		return x.Length
+	}
+	return 0
+}
+
+func (x *RouteResponse) GetDuration() float64 {
+	if x != nil {
+		return x.Duration
+	}
+	return 0
+}
+
+func (x *RouteResponse) GetViolations() []string {
+	if x != nil {
+		return x.Violations
+	}
+	return nil
+}
+
+func (x *RouteResponse) GetDirections() []string {
+	if x != nil {
+		return x.Directions
+	}
+	return nil
+}
+
+// Progress is a better solution found during the search; the last one is final
+type Progress struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Order         *Order                 `protobuf:"bytes,1,opt,name=order,proto3" json:"order,omitempty"`
+	Length        float64                `protobuf:"fixed64,2,opt,name=length,proto3" json:"length,omitempty"` // meters, like the length of a RouteResponse
+	Final         bool                   `protobuf:"varint,3,opt,name=final,proto3" json:"final,omitempty"`
+	Route         *RouteOrder            `protobuf:"bytes,4,opt,name=route,proto3" json:"route,omitempty"` // set on the final solution
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Progress) Reset() {
+	*x = Progress{}
+	mi := &file_warehouse_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Progress) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Progress) ProtoMessage() {}
+
+func (x *Progress) ProtoReflect() protoreflect.Message {
+	mi := &file_warehouse_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Progress.ProtoReflect.Descriptor instead.
+func (*Progress) Descriptor() ([]byte, []int) {
+	return file_warehouse_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *Progress) GetOrder() *Order {
+	if x != nil {
+		return x.Order
+	}
+	return nil
+}
+
+func (x *Progress) GetLength() float64 {
+	if x != nil {
+		return x.Length
+	}
+	return 0
+}
+
+func (x *Progress) GetFinal() bool {
+	if x != nil {
+		return x.Final
+	}
+	return false
+}
+
+func (x *Progress) GetRoute() *RouteOrder {
+	if x != nil {
+		return x.Route
+	}
+	return nil
+}
+
+// BatchRequest asks for the routes of orders merged into batches for the cart
+type BatchRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Orders        []*Order               `protobuf:"bytes,1,rep,name=orders,proto3" json:"orders,omitempty"`
+	Start         *Point                 `protobuf:"bytes,2,opt,name=start,proto3" json:"start,omitempty"`
+	End           *Point                 `protobuf:"bytes,3,opt,name=end,proto3" json:"end,omitempty"`
+	Optimizer     Optimizer              `protobuf:"varint,4,opt,name=optimizer,proto3,enum=warehouse.Optimizer" json:"optimizer,omitempty"`
+	Objective     Objective              `protobuf:"varint,5,opt,name=objective,proto3,enum=warehouse.Objective" json:"objective,omitempty"`
+	TimeLimit     float64                `protobuf:"fixed64,6,opt,name=time_limit,json=timeLimit,proto3" json:"time_limit,omitempty"`
+	Weight        float64                `protobuf:"fixed64,7,opt,name=weight,proto3" json:"weight,omitempty"` // weight limit of the cart, 0 for the configured one
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchRequest) Reset() {
+	*x = BatchRequest{}
+	mi := &file_warehouse_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchRequest) ProtoMessage() {}
+
+func (x *BatchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_warehouse_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchRequest.ProtoReflect.Descriptor instead.
+func (*BatchRequest) Descriptor() ([]byte, []int) {
+	return file_warehouse_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *BatchRequest) GetOrders() []*Order {
+	if x != nil {
+		return x.Orders
+	}
+	return nil
+}
+
+func (x *BatchRequest) GetStart() *Point {
+	if x != nil {
+		return x.Start
+	}
+	return nil
+}
+
+func (x *BatchRequest) GetEnd() *Point {
+	if x != nil {
+		return x.End
+	}
+	return nil
+}
+
+func (x *BatchRequest) GetOptimizer() Optimizer {
+	if x != nil {
+		return x.Optimizer
+	}
+	return Optimizer_OPTIMIZER_NNI
+}
+
+func (x *BatchRequest) GetObjective() Objective {
+	if x != nil {
+		return x.Objective
+	}
+	return Objective_OBJECTIVE_DISTANCE
+}
+
+func (x *BatchRequest) GetTimeLimit() float64 {
+	if x != nil {
+		return x.TimeLimit
+	}
+	return 0
+}
+
+func (x *BatchRequest) GetWeight() float64 {
+	if x != nil {
+		return x.Weight
+	}
+	return 0
+}
+
+type BatchResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Routes        []*RouteOrder          `protobuf:"bytes,1,rep,name=routes,proto3" json:"routes,omitempty"`
+	Plan          *Plan                  `protobuf:"bytes,2,opt,name=plan,proto3" json:"plan,omitempty"` // the routes as a plan, see schema/plan.v1.schema.json
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchResponse) Reset() {
+	*x = BatchResponse{}
+	mi := &file_warehouse_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchResponse) ProtoMessage() {}
+
+func (x *BatchResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_warehouse_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchResponse.ProtoReflect.Descriptor instead.
+func (*BatchResponse) Descriptor() ([]byte, []int) {
+	return file_warehouse_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *BatchResponse) GetRoutes() []*RouteOrder {
+	if x != nil {
+		return x.Routes
+	}
+	return nil
+}
+
+func (x *BatchResponse) GetPlan() *Plan {
+	if x != nil {
+		return x.Plan
+	}
+	return nil
+}
+
+// PlanMetadata tells how a plan was made
+type PlanMetadata struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Algorithm      string                 `protobuf:"bytes,1,opt,name=algorithm,proto3" json:"algorithm,omitempty"`
+	Objective      string                 `protobuf:"bytes,2,opt,name=objective,proto3" json:"objective,omitempty"`
+	Created        string                 `protobuf:"bytes,3,opt,name=created,proto3" json:"created,omitempty"` // RFC 3339
+	RuntimeSeconds float64                `protobuf:"fixed64,4,opt,name=runtime_seconds,json=runtimeSeconds,proto3" json:"runtime_seconds,omitempty"`
+	LowerBound     float64                `protobuf:"fixed64,5,opt,name=lower_bound,json=lowerBound,proto3" json:"lower_bound,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *PlanMetadata) Reset() {
+	*x = PlanMetadata{}
+	mi := &file_warehouse_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PlanMetadata) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PlanMetadata) ProtoMessage() {}
+
+func (x *PlanMetadata) ProtoReflect() protoreflect.Message {
+	mi := &file_warehouse_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PlanMetadata.ProtoReflect.Descriptor instead.
+func (*PlanMetadata) Descriptor() ([]byte, []int) {
+	return file_warehouse_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *PlanMetadata) GetAlgorithm() string {
+	if x != nil {
+		return x.Algorithm
+	}
+	return ""
+}
+
+func (x *PlanMetadata) GetObjective() string {
+	if x != nil {
+		return x.Objective
+	}
+	return ""
+}
+
+func (x *PlanMetadata) GetCreated() string {
+	if x != nil {
+		return x.Created
+	}
+	return ""
+}
+
+func (x *PlanMetadata) GetRuntimeSeconds() float64 {
+	if x != nil {
+		return x.RuntimeSeconds
+	}
+	return 0
+}
+
+func (x *PlanMetadata) GetLowerBound() float64 {
+	if x != nil {
+		return x.LowerBound
+	}
+	return 0
+}
+
+// PlanStep is a walk along path or the pick of sku for an order
+type PlanStep struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Type          string                 `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"` // "walk" or "pick"
+	Path          []*Point               `protobuf:"bytes,2,rep,name=path,proto3" json:"path,omitempty"`
+	Length        float64                `protobuf:"fixed64,3,opt,name=length,proto3" json:"length,omitempty"` // meters
+	Sku           int32                  `protobuf:"varint,4,opt,name=sku,proto3" json:"sku,omitempty"`
+	OrderId       int32                  `protobuf:"varint,5,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	Location      *Point                 `protobuf:"bytes,6,opt,name=location,proto3" json:"location,omitempty"` // shelf of the product
+	Access        *Point                 `protobuf:"bytes,7,opt,name=access,proto3" json:"access,omitempty"`     // aisle cell the product is picked from
+	Level         int32                  `protobuf:"varint,8,opt,name=level,proto3" json:"level,omitempty"`
+	Weight        *float64               `protobuf:"fixed64,9,opt,name=weight,proto3,oneof" json:"weight,omitempty"` // absent when unknown
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PlanStep) Reset() {
+	*x = PlanStep{}
+	mi := &file_warehouse_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PlanStep) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PlanStep) ProtoMessage() {}
+
+func (x *PlanStep) ProtoReflect() protoreflect.Message {
+	mi := &file_warehouse_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PlanStep.ProtoReflect.Descriptor instead.
+func (*PlanStep) Descriptor() ([]byte, []int) {
+	return file_warehouse_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *PlanStep) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *PlanStep) GetPath() []*Point {
+	if x != nil {
+		return x.Path
+	}
+	return nil
+}
+
+func (x *PlanStep) GetLength() float64 {
+	if x != nil {
+		return x.Length
+	}
+	return 0
+}
+
+func (x *PlanStep) GetSku() int32 {
+	if x != nil {
+		return x.Sku
+	}
+	return 0
+}
+
+func (x *PlanStep) GetOrderId() int32 {
+	if x != nil {
+		return x.OrderId
+	}
+	return 0
+}
+
+func (x *PlanStep) GetLocation() *Point {
+	if x != nil {
+		return x.Location
+	}
+	return nil
+}
+
+func (x *PlanStep) GetAccess() *Point {
+	if x != nil {
+		return x.Access
+	}
+	return nil
+}
+
+func (x *PlanStep) GetLevel() int32 {
+	if x != nil {
+		return x.Level
+	}
+	return 0
+}
+
+func (x *PlanStep) GetWeight() float64 {
+	if x != nil && x.Weight != nil {
+		return *x.Weight
+	}
+	return 0
+}
+
+// PlanTotals sums up a trip or a plan; length is in meters
+type PlanTotals struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Trips         int32                  `protobuf:"varint,1,opt,name=trips,proto3" json:"trips,omitempty"`
+	Items         int32                  `protobuf:"varint,2,opt,name=items,proto3" json:"items,omitempty"`
+	Length        float64                `protobuf:"fixed64,3,opt,name=length,proto3" json:"length,omitempty"`
+	Effort        float64                `protobuf:"fixed64,4,opt,name=effort,proto3" json:"effort,omitempty"`
+	Weight        float64                `protobuf:"fixed64,5,opt,name=weight,proto3" json:"weight,omitempty"`
+	MissingWeight bool                   `protobuf:"varint,6,opt,name=missing_weight,json=missingWeight,proto3" json:"missing_weight,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PlanTotals) Reset() {
+	*x = PlanTotals{}
+	mi := &file_warehouse_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PlanTotals) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PlanTotals) ProtoMessage() {}
+
+func (x *PlanTotals) ProtoReflect() protoreflect.Message {
+	mi := &file_warehouse_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PlanTotals.ProtoReflect.Descriptor instead.
+func (*PlanTotals) Descriptor() ([]byte, []int) {
+	return file_warehouse_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *PlanTotals) GetTrips() int32 {
+	if x != nil {
+		return x.Trips
+	}
+	return 0
+}
+
+func (x *PlanTotals) GetItems() int32 {
+	if x != nil {
+		return x.Items
+	}
+	return 0
+}
+
+func (x *PlanTotals) GetLength() float64 {
+	if x != nil {
+		return x.Length
+	}
+	return 0
+}
+
+func (x *PlanTotals) GetEffort() float64 {
+	if x != nil {
+		return x.Effort
+	}
+	return 0
+}
+
+func (x *PlanTotals) GetWeight() float64 {
+	if x != nil {
+		return x.Weight
+	}
+	return 0
+}
+
+func (x *PlanTotals) GetMissingWeight() bool {
+	if x != nil {
+		return x.MissingWeight
+	}
+	return false
+}
+
+type PlanTrip struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Batch         int32                  `protobuf:"varint,1,opt,name=batch,proto3" json:"batch,omitempty"`
+	Start         *Point                 `protobuf:"bytes,2,opt,name=start,proto3" json:"start,omitempty"`
+	End           *Point                 `protobuf:"bytes,3,opt,name=end,proto3" json:"end,omitempty"`
+	Steps         []*PlanStep            `protobuf:"bytes,4,rep,name=steps,proto3" json:"steps,omitempty"`
+	Totals        *PlanTotals            `protobuf:"bytes,5,opt,name=totals,proto3" json:"totals,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PlanTrip) Reset() {
+	*x = PlanTrip{}
+	mi := &file_warehouse_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PlanTrip) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PlanTrip) ProtoMessage() {}
+
+func (x *PlanTrip) ProtoReflect() protoreflect.Message {
+	mi := &file_warehouse_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PlanTrip.ProtoReflect.Descriptor instead.
+func (*PlanTrip) Descriptor() ([]byte, []int) {
+	return file_warehouse_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *PlanTrip) GetBatch() int32 {
+	if x != nil {
+		return x.Batch
+	}
+	return 0
+}
+
+func (x *PlanTrip) GetStart() *Point {
+	if x != nil {
+		return x.Start
+	}
+	return nil
+}
+
+func (x *PlanTrip) GetEnd() *Point {
+	if x != nil {
+		return x.End
+	}
+	return nil
+}
+
+func (x *PlanTrip) GetSteps() []*PlanStep {
+	if x != nil {
+		return x.Steps
+	}
+	return nil
+}
+
+func (x *PlanTrip) GetTotals() *PlanTotals {
+	if x != nil {
+		return x.Totals
+	}
+	return nil
+}
+
+// Plan is the versioned plan of the optimizer
+type Plan struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Version       int32                  `protobuf:"varint,1,opt,name=version,proto3" json:"version,omitempty"`
+	Metadata      *PlanMetadata          `protobuf:"bytes,2,opt,name=metadata,proto3" json:"metadata,omitempty"`
+	Trips         []*PlanTrip            `protobuf:"bytes,3,rep,name=trips,proto3" json:"trips,omitempty"`
+	Totals        *PlanTotals            `protobuf:"bytes,4,opt,name=totals,proto3" json:"totals,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Plan) Reset() {
+	*x = Plan{}
+	mi := &file_warehouse_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Plan) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Plan) ProtoMessage() {}
+
+func (x *Plan) ProtoReflect() protoreflect.Message {
+	mi := &file_warehouse_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Plan.ProtoReflect.Descriptor instead.
+func (*Plan) Descriptor() ([]byte, []int) {
+	return file_warehouse_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *Plan) GetVersion() int32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *Plan) GetMetadata() *PlanMetadata {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+func (x *Plan) GetTrips() []*PlanTrip {
+	if x != nil {
+		return x.Trips
+	}
+	return nil
+}
+
+func (x *Plan) GetTotals() *PlanTotals {
+	if x != nil {
+		return x.Totals
+	}
+	return nil
+}
+
+var File_warehouse_proto protoreflect.FileDescriptor
+
+const file_warehouse_proto_rawDesc = "" +
+	"\n" +
+	"\x0fwarehouse.proto\x12\twarehouse\"E\n" +
+	"\x05Point\x12\f\n" +
+	"\x01x\x18\x01 \x01(\x05R\x01x\x12\f\n" +
+	"\x01y\x18\x02 \x01(\x05R\x01y\x12\f\n" +
+	"\x01f\x18\x03 \x01(\x05R\x01f\x12\x12\n" +
+	"\x04name\x18\x04 \x01(\tR\x04name\":\n" +
+	"\x04Item\x12\x17\n" +
+	"\aprod_id\x18\x01 \x01(\x05R\x06prodId\x12\x19\n" +
+	"\border_id\x18\x02 \x01(\x05R\aorderId\".\n" +
+	"\x05Order\x12%\n" +
+	"\x05items\x18\x01 \x03(\v2\x0f.warehouse.ItemR\x05items\"\x84\x01\n" +
+	"\aProduct\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x05R\x02id\x12\"\n" +
+	"\x03pos\x18\x02 \x01(\v2\x10.warehouse.PointR\x03pos\x12\x14\n" +
+	"\x05level\x18\x03 \x01(\x05R\x05level\x12\x14\n" +
+	"\x05class\x18\x04 \x01(\tR\x05class\x12\x19\n" +
+	"\border_id\x18\x05 \x01(\x05R\aorderId\"0\n" +
+	"\x04Path\x12(\n" +
+	"\x06points\x18\x01 \x03(\v2\x10.warehouse.PointR\x06points\"\xe7\x01\n" +
+	"\x04Trip\x12&\n" +
+	"\x05order\x18\x01 \x01(\v2\x10.warehouse.OrderR\x05order\x12.\n" +
+	"\bproducts\x18\x02 \x03(\v2\x12.warehouse.ProductR\bproducts\x12#\n" +
+	"\x04path\x18\x03 \x01(\v2\x0f.warehouse.PathR\x04path\x12\x16\n" +
+	"\x06length\x18\x04 \x01(\x01R\x06length\x12&\n" +
+	"\x05start\x18\x05 \x01(\v2\x10.warehouse.PointR\x05start\x12\"\n" +
+	"\x03end\x18\x06 \x01(\v2\x10.warehouse.PointR\x03end\"\x7f\n" +
+	"\n" +
+	"RouteOrder\x12%\n" +
+	"\x05trips\x18\x01 \x03(\v2\x0f.warehouse.TripR\x05trips\x12&\n" +
+	"\x05start\x18\x02 \x01(\v2\x10.warehouse.PointR\x05start\x12\"\n" +
+	"\x03end\x18\x03 \x01(\v2\x10.warehouse.PointR\x03end\"\x89\x02\n" +
+	"\fRouteRequest\x12&\n" +
+	"\x05order\x18\x01 \x01(\v2\x10.warehouse.OrderR\x05order\x12&\n" +
+	"\x05start\x18\x02 \x01(\v2\x10.warehouse.PointR\x05start\x12\"\n" +
+	"\x03end\x18\x03 \x01(\v2\x10.warehouse.PointR\x03end\x122\n" +
+	"\toptimizer\x18\x04 \x01(\x0e2\x14.warehouse.OptimizerR\toptimizer\x122\n" +
+	"\tobjective\x18\x05 \x01(\x0e2\x14.warehouse.ObjectiveR\tobjective\x12\x1d\n" +
+	"\n" +
+	"time_limit\x18\x06 \x01(\x01R\ttimeLimit\"\xb0\x01\n" +
+	"\rRouteResponse\x12+\n" +
+	"\x05route\x18\x01 \x01(\v2\x15.warehouse.RouteOrderR\x05route\x12\x16\n" +
+	"\x06length\x18\x02 \x01(\x01R\x06length\x12\x1a\n" +
+	"\bduration\x18\x03 \x01(\x01R\bduration\x12\x1e\n" +
+	"\n" +
+	"violations\x18\x04 \x03(\tR\n" +
+	"violations\x12\x1e\n" +
+	"\n" +
+	"directions\x18\x05 \x03(\tR\n" +
+	"directions\"\x8d\x01\n" +
+	"\bProgress\x12&\n" +
+	"\x05order\x18\x01 \x01(\v2\x10.warehouse.OrderR\x05order\x12\x16\n" +
+	"\x06length\x18\x02 \x01(\x01R\x06length\x12\x14\n" +
+	"\x05final\x18\x03 \x01(\bR\x05final\x12+\n" +
+	"\x05route\x18\x04 \x01(\v2\x15.warehouse.RouteOrderR\x05route\"\xa3\x02\n" +
+	"\fBatchRequest\x12(\n" +
+	"\x06orders\x18\x01 \x03(\v2\x10.warehouse.OrderR\x06orders\x12&\n" +
+	"\x05start\x18\x02 \x01(\v2\x10.warehouse.PointR\x05start\x12\"\n" +
+	"\x03end\x18\x03 \x01(\v2\x10.warehouse.PointR\x03end\x122\n" +
+	"\toptimizer\x18\x04 \x01(\x0e2\x14.warehouse.OptimizerR\toptimizer\x122\n" +
+	"\tobjective\x18\x05 \x01(\x0e2\x14.warehouse.ObjectiveR\tobjective\x12\x1d\n" +
+	"\n" +
+	"time_limit\x18\x06 \x01(\x01R\ttimeLimit\x12\x16\n" +
+	"\x06weight\x18\a \x01(\x01R\x06weight\"c\n" +
+	"\rBatchResponse\x12-\n" +
+	"\x06routes\x18\x01 \x03(\v2\x15.warehouse.RouteOrderR\x06routes\x12#\n" +
+	"\x04plan\x18\x02 \x01(\v2\x0f.warehouse.PlanR\x04plan\"\xae\x01\n" +
+	"\fPlanMetadata\x12\x1c\n" +
+	"\talgorithm\x18\x01 \x01(\tR\talgorithm\x12\x1c\n" +
+	"\tobjective\x18\x02 \x01(\tR\tobjective\x12\x18\n" +
+	"\acreated\x18\x03 \x01(\tR\acreated\x12'\n" +
+	"\x0fruntime_seconds\x18\x04 \x01(\x01R\x0eruntimeSeconds\x12\x1f\n" +
+	"\vlower_bound\x18\x05 \x01(\x01R\n" +
+	"lowerBound\"\x9f\x02\n" +
+	"\bPlanStep\x12\x12\n" +
+	"\x04type\x18\x01 \x01(\tR\x04type\x12$\n" +
+	"\x04path\x18\x02 \x03(\v2\x10.warehouse.PointR\x04path\x12\x16\n" +
+	"\x06length\x18\x03 \x01(\x01R\x06length\x12\x10\n" +
+	"\x03sku\x18\x04 \x01(\x05R\x03sku\x12\x19\n" +
+	"\border_id\x18\x05 \x01(\x05R\aorderId\x12,\n" +
+	"\blocation\x18\x06 \x01(\v2\x10.warehouse.PointR\blocation\x12(\n" +
+	"\x06access\x18\a \x01(\v2\x10.warehouse.PointR\x06access\x12\x14\n" +
+	"\x05level\x18\b \x01(\x05R\x05level\x12\x1b\n" +
+	"\x06weight\x18\t \x01(\x01H\x00R\x06weight\x88\x01\x01B\t\n" +
+	"\a_weight\"\xa7\x01\n" +
+	"\n" +
+	"PlanTotals\x12\x14\n" +
+	"\x05trips\x18\x01 \x01(\x05R\x05trips\x12\x14\n" +
+	"\x05items\x18\x02 \x01(\x05R\x05items\x12\x16\n" +
+	"\x06length\x18\x03 \x01(\x01R\x06length\x12\x16\n" +
+	"\x06effort\x18\x04 \x01(\x01R\x06effort\x12\x16\n" +
+	"\x06weight\x18\x05 \x01(\x01R\x06weight\x12%\n" +
+	"\x0emissing_weight\x18\x06 \x01(\bR\rmissingWeight\"\xc6\x01\n" +
+	"\bPlanTrip\x12\x14\n" +
+	"\x05batch\x18\x01 \x01(\x05R\x05batch\x12&\n" +
+	"\x05start\x18\x02 \x01(\v2\x10.warehouse.PointR\x05start\x12\"\n" +
+	"\x03end\x18\x03 \x01(\v2\x10.warehouse.PointR\x03end\x12)\n" +
+	"\x05steps\x18\x04 \x03(\v2\x13.warehouse.PlanStepR\x05steps\x12-\n" +
+	"\x06totals\x18\x05 \x01(\v2\x15.warehouse.PlanTotalsR\x06totals\"\xaf\x01\n" +
+	"\x04Plan\x12\x18\n" +
+	"\aversion\x18\x01 \x01(\x05R\aversion\x123\n" +
+	"\bmetadata\x18\x02 \x01(\v2\x17.warehouse.PlanMetadataR\bmetadata\x12)\n" +
+	"\x05trips\x18\x03 \x03(\v2\x13.warehouse.PlanTripR\x05trips\x12-\n" +
+	"\x06totals\x18\x04 \x01(\v2\x15.warehouse.PlanTotalsR\x06totals*1\n" +
+	"\tOptimizer\x12\x11\n" +
+	"\rOPTIMIZER_NNI\x10\x00\x12\x11\n" +
+	"\rOPTIMIZER_BNB\x10\x01*7\n" +
+	"\tObjective\x12\x16\n" +
+	"\x12OBJECTIVE_DISTANCE\x10\x00\x12\x12\n" +
+	"\x0eOBJECTIVE_TIME\x10\x012\xc1\x01\n" +
+	"\x06Router\x12:\n" +
+	"\x05Route\x12\x17.warehouse.RouteRequest\x1a\x18.warehouse.RouteResponse\x12?\n" +
+	"\rRouteProgress\x12\x17.warehouse.RouteRequest\x1a\x13.warehouse.Progress0\x01\x12:\n" +
+	"\x05Batch\x12\x17.warehouse.BatchRequest\x1a\x18.warehouse.BatchResponseB!Z\x1fwarehouse-optimizer/warehousepbb\x06proto3"
+
+var (
+	file_warehouse_proto_rawDescOnce sync.Once
+	file_warehouse_proto_rawDescData []byte
+)
+
+func file_warehouse_proto_rawDescGZIP() []byte {
+	file_warehouse_proto_rawDescOnce.Do(func() {
+		file_warehouse_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_warehouse_proto_rawDesc), len(file_warehouse_proto_rawDesc)))
+	})
+	return file_warehouse_proto_rawDescData
+}
+
+var file_warehouse_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
+var file_warehouse_proto_msgTypes = make([]protoimpl.MessageInfo, 17)
+var file_warehouse_proto_goTypes = []any{
+	(Optimizer)(0),        // 0: warehouse.Optimizer
+	(Objective)(0),        // 1: warehouse.Objective
+	(*Point)(nil),         // 2: warehouse.Point
+	(*Item)(nil),          // 3: warehouse.Item
+	(*Order)(nil),         // 4: warehouse.Order
+	(*Product)(nil),       // 5: warehouse.Product
+	(*Path)(nil),          // 6: warehouse.Path
+	(*Trip)(nil),          // 7: warehouse.Trip
+	(*RouteOrder)(nil),    // 8: warehouse.RouteOrder
+	(*RouteRequest)(nil),  // 9: warehouse.RouteRequest
+	(*RouteResponse)(nil), // 10: warehouse.RouteResponse
+	(*Progress)(nil),      // 11: warehouse.Progress
+	(*BatchRequest)(nil),  // 12: warehouse.BatchRequest
+	(*BatchResponse)(nil), // 13: warehouse.BatchResponse
+	(*PlanMetadata)(nil),  // 14: warehouse.PlanMetadata
+	(*PlanStep)(nil),      // 15: warehouse.PlanStep
+	(*PlanTotals)(nil),    // 16: warehouse.PlanTotals
+	(*PlanTrip)(nil),      // 17: warehouse.PlanTrip
+	(*Plan)(nil),          // 18: warehouse.Plan
+}
+var file_warehouse_proto_depIdxs = []int32{
+	3,  // 0: warehouse.Order.items:type_name -> warehouse.Item
+	2,  // 1: warehouse.Product.pos:type_name -> warehouse.Point
+	2,  // 2: warehouse.Path.points:type_name -> warehouse.Point
+	4,  // 3: warehouse.Trip.order:type_name -> warehouse.Order
+	5,  // 4: warehouse.Trip.products:type_name -> warehouse.Product
+	6,  // 5: warehouse.Trip.path:type_name -> warehouse.Path
+	2,  // 6: warehouse.Trip.start:type_name -> warehouse.Point
+	2,  // 7: warehouse.Trip.end:type_name -> warehouse.Point
+	7,  // 8: warehouse.RouteOrder.trips:type_name -> warehouse.Trip
+	2,  // 9: warehouse.RouteOrder.start:type_name -> warehouse.Point
+	2,  // 10: warehouse.RouteOrder.end:type_name -> warehouse.Point
+	4,  // 11: warehouse.RouteRequest.order:type_name -> warehouse.Order
+	2,  // 12: warehouse.RouteRequest.start:type_name -> warehouse.Point
+	2,  // 13: warehouse.RouteRequest.end:type_name -> warehouse.Point
+	0,  // 14: warehouse.RouteRequest.optimizer:type_name -> warehouse.Optimizer
+	1,  // 15: warehouse.RouteRequest.objective:type_name -> warehouse.Objective
+	8,  // 16: warehouse.RouteResponse.route:type_name -> warehouse.RouteOrder
+	4,  // 17: warehouse.Progress.order:type_name -> warehouse.Order
+	8,  // 18: warehouse.Progress.route:type_name -> warehouse.RouteOrder
+	4,  // 19: warehouse.BatchRequest.orders:type_name -> warehouse.Order
+	2,  // 20: warehouse.BatchRequest.start:type_name -> warehouse.Point
+	2,  // 21: warehouse.BatchRequest.end:type_name -> warehouse.Point
+	0,  // 22: warehouse.BatchRequest.optimizer:type_name -> warehouse.Optimizer
+	1,  // 23: warehouse.BatchRequest.objective:type_name -> warehouse.Objective
+	8,  // 24: warehouse.BatchResponse.routes:type_name -> warehouse.RouteOrder
+	18, // 25: warehouse.BatchResponse.plan:type_name -> warehouse.Plan
+	2,  // 26: warehouse.PlanStep.path:type_name -> warehouse.Point
+	2,  // 27: warehouse.PlanStep.location:type_name -> warehouse.Point
+	2,  // 28: warehouse.PlanStep.access:type_name -> warehouse.Point
+	2,  // 29: warehouse.PlanTrip.start:type_name -> warehouse.Point
+	2,  // 30: warehouse.PlanTrip.end:type_name -> warehouse.Point
+	15, // 31: warehouse.PlanTrip.steps:type_name -> warehouse.PlanStep
+	16, // 32: warehouse.PlanTrip.totals:type_name -> warehouse.PlanTotals
+	14, // 33: warehouse.Plan.metadata:type_name -> warehouse.PlanMetadata
+	17, // 34: warehouse.Plan.trips:type_name -> warehouse.PlanTrip
+	16, // 35: warehouse.Plan.totals:type_name -> warehouse.PlanTotals
+	9,  // 36: warehouse.Router.Route:input_type -> warehouse.RouteRequest
+	9,  // 37: warehouse.Router.RouteProgress:input_type -> warehouse.RouteRequest
+	12, // 38: warehouse.Router.Batch:input_type -> warehouse.BatchRequest
+	10, // 39: warehouse.Router.Route:output_type -> warehouse.RouteResponse
+	11, // 40: warehouse.Router.RouteProgress:output_type -> warehouse.Progress
+	13, // 41: warehouse.Router.Batch:output_type -> warehouse.BatchResponse
+	39, // [39:42] is the sub-list for method output_type
+	36, // [36:39] is the sub-list for method input_type
+	36, // [36:36] is the sub-list for extension type_name
+	36, // [36:36] is the sub-list for extension extendee
+	0,  // [0:36] is the sub-list for field type_name
+}
+
+func init() { file_warehouse_proto_init() }
+func file_warehouse_proto_init() {
+	if File_warehouse_proto != nil {
+		return
+	}
+	file_warehouse_proto_msgTypes[13].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_warehouse_proto_rawDesc), len(file_warehouse_proto_rawDesc)),
+			NumEnums:      2,
+			NumMessages:   17,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_warehouse_proto_goTypes,
+		DependencyIndexes: file_warehouse_proto_depIdxs,
+		EnumInfos:         file_warehouse_proto_enumTypes,
+		MessageInfos:      file_warehouse_proto_msgTypes,
+	}.Build()
+	File_warehouse_proto = out.File
+	file_warehouse_proto_goTypes = nil
+	file_warehouse_proto_depIdxs = nil
+}